@@ -0,0 +1,113 @@
+package vector
+
+import mathbits "math/bits"
+
+// BitVector is an immutable vector of booleans.  Bits are packed 64 to a
+// word, so a BitVector uses a small fraction of the memory of the
+// equivalent Vector[bool].
+type BitVector struct {
+	n     int
+	words Vector[uint64]
+}
+
+// NewBitVector returns a BitVector containing n false bits.
+func NewBitVector(n int) BitVector {
+	if n <= 0 {
+		return BitVector{}
+	}
+
+	b := NewBuilder[uint64]()
+	for i := 0; i < (n+63)/64; i++ {
+		b.Cons(0)
+	}
+
+	return BitVector{n: n, words: b.Vector()}
+}
+
+// Len returns the number of bits contained in the BitVector.
+func (v BitVector) Len() int {
+	return v.n
+}
+
+// At returns the ith bit in the BitVector.
+func (v BitVector) At(i int) bool {
+	if i < 0 || i >= v.n {
+		panic("index out of bounds")
+	}
+
+	return v.words.At(i/64)&(1<<(i%64)) != 0
+}
+
+// Set returns a copy of the BitVector with the bit at index set to b.
+// As with Vector.Set, an index equal to Len() appends b.
+func (v BitVector) Set(index int, b bool) BitVector {
+	if index == v.n {
+		return v.Append(b)
+	}
+
+	if index < 0 || index > v.n {
+		panic("index out of bounds")
+	}
+
+	w := v.words.At(index / 64)
+	if b {
+		w |= 1 << (index % 64)
+	} else {
+		w &^= 1 << (index % 64)
+	}
+
+	return BitVector{n: v.n, words: v.words.Set(index/64, w)}
+}
+
+// Append bits to the BitVector.  Bits are collected into whole words, so
+// that each word of the BitVector is written at most once.
+func (v BitVector) Append(bs ...bool) BitVector {
+	if len(bs) == 0 {
+		return v
+	}
+
+	words := v.words.transient()
+
+	// flush stores the word holding bit v.n-1, which is the last word of
+	// the original vector if it was partially filled, or a new one.
+	var w uint64
+	flush := func() {
+		if i := (v.n - 1) / 64; i < words.Len() {
+			words.Set(i, w)
+		} else {
+			words.Cons(w)
+		}
+	}
+
+	if v.n%64 != 0 {
+		w = v.words.At(v.n / 64)
+	}
+
+	for _, b := range bs {
+		if b {
+			w |= 1 << (v.n % 64)
+		}
+
+		if v.n++; v.n%64 == 0 {
+			flush()
+			w = 0
+		}
+	}
+
+	if v.n%64 != 0 {
+		flush()
+	}
+
+	v.words = words.Vector()
+	return v
+}
+
+// PopCount returns the number of bits that are set.
+func (v BitVector) PopCount() (n int) {
+	v.words.forEach(func(_ int, w uint64) bool {
+		n += mathbits.OnesCount64(w)
+		return true
+	})
+
+	return
+}
@@ -0,0 +1,110 @@
+package vector_test
+
+import (
+	"testing"
+
+	"github.com/lthibault/vector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBitVector(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	t.Run("ZeroValue", func(t *testing.T) {
+		var v vector.BitVector
+		assert.Zero(t, v.Len(), "zero-value bit vector should have zero length")
+		assert.Zero(t, v.PopCount(), "zero-value bit vector should have no bits set")
+	})
+
+	t.Run("New", func(t *testing.T) {
+		v := vector.NewBitVector(n + 1)
+		require.Equal(t, n+1, v.Len(), "should contain %d bits", n+1)
+		assert.Zero(t, v.PopCount(), "bits should be unset")
+		assert.False(t, v.At(n), "bits should be unset")
+	})
+
+	t.Run("Append", func(t *testing.T) {
+		var v vector.BitVector
+		for i := 0; i < n; i++ {
+			v = v.Append(i%3 == 0)
+		}
+
+		require.Equal(t, n, v.Len(), "should contain %d bits", n)
+		for i := 0; i < n; i++ {
+			require.Equal(t, i%3 == 0, v.At(i), "bit %d", i)
+		}
+
+		assert.Equal(t, (n+2)/3, v.PopCount(), "should count set bits")
+
+		bulk := vector.NewBitVector(0).Append(true, false, true)
+		assert.Equal(t, 3, bulk.Len(), "should bulk-append bits")
+		assert.Equal(t, 2, bulk.PopCount())
+	})
+
+	t.Run("AppendRuns", func(t *testing.T) {
+		// runs that start and end at various offsets within a word
+		var want []bool
+		var v vector.BitVector
+		for _, k := range []int{1, 63, 64, 65, 3, 200, 127, 0, 1000} {
+			run := make([]bool, k)
+			for i := range run {
+				run[i] = (len(want)+i)%5 < 2
+			}
+
+			prev := v
+			v = v.Append(run...)
+			want = append(want, run...)
+
+			require.Equal(t, len(want)-k, prev.Len(), "should not mutate receiver")
+			require.Equal(t, popCount(want[:prev.Len()]), prev.PopCount(),
+				"should not set bits in the receiver")
+			require.Equal(t, len(want), v.Len())
+			for i, b := range want {
+				require.Equal(t, b, v.At(i), "bit %d", i)
+			}
+		}
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		v := vector.NewBitVector(n)
+
+		v2 := v
+		for i := 0; i < n; i += 2 {
+			v2 = v2.Set(i, true)
+		}
+
+		assert.Zero(t, v.PopCount(), "should not mutate receiver")
+		assert.Equal(t, n/2, v2.PopCount())
+		assert.True(t, v2.At(n-2))
+		assert.False(t, v2.At(n-1))
+
+		v3 := v2.Set(n-2, false)
+		assert.False(t, v3.At(n-2), "should clear bit")
+		assert.True(t, v2.At(n-2), "should not mutate receiver")
+
+		v4 := v2.Set(n, true)
+		assert.Equal(t, n+1, v4.Len(), "should append bit")
+		assert.True(t, v4.At(n))
+	})
+
+	t.Run("OutOfBounds", func(t *testing.T) {
+		v := vector.NewBitVector(10)
+		assert.Panics(t, func() { v.At(10) }, "should panic when out of bounds")
+		assert.Panics(t, func() { v.At(-1) }, "should panic when out of bounds")
+		assert.Panics(t, func() { v.Set(11, true) }, "should panic when out of bounds")
+		assert.Panics(t, func() { v.Set(-1, true) }, "should panic when out of bounds")
+	})
+}
+
+func popCount(bs []bool) (n int) {
+	for _, b := range bs {
+		if b {
+			n++
+		}
+	}
+
+	return
+}
@@ -0,0 +1,39 @@
+package vector
+
+import (
+	"fmt"
+	"io"
+)
+
+// WriteChunked encodes v in consecutive chunks of chunkSize elements, and
+// writes each encoded chunk to w in order.  The final chunk may be shorter.
+// The slice passed to enc is reused between calls.  WriteChunked returns
+// the number of bytes written, and stops at the first error.  It panics if
+// chunkSize <= 0.
+func (v Vector[T]) WriteChunked(w io.Writer, chunkSize int, enc func([]T) ([]byte, error)) (int64, error) {
+	if chunkSize <= 0 {
+		panic("non-positive chunk size")
+	}
+
+	var total int64
+	chunk := make([]T, min(chunkSize, v.cnt))
+	for start := 0; start < v.cnt; start += chunkSize {
+		k := v.ReadAt(chunk, start)
+
+		buf, err := enc(chunk[:k])
+		if err != nil {
+			return total, fmt.Errorf("chunk at index %d: %w", start, err)
+		}
+
+		n, err := w.Write(buf)
+		total += int64(n)
+		if err == nil && n < len(buf) {
+			err = io.ErrShortWrite
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
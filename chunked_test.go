@@ -0,0 +1,78 @@
+package vector_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/lthibault/vector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteChunked(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	v := vector.Iota(0, n)
+
+	// each frame is a length byte followed by one uint16 per record
+	frame := func(chunk []int) ([]byte, error) {
+		buf := []byte{byte(len(chunk))}
+		for _, x := range chunk {
+			buf = binary.BigEndian.AppendUint16(buf, uint16(x))
+		}
+		return buf, nil
+	}
+
+	var buf bytes.Buffer
+	written, err := v.WriteChunked(&buf, 100, frame)
+	require.NoError(t, err)
+	require.Equal(t, int64(buf.Len()), written)
+	require.Equal(t, 41+2*n, buf.Len(), "should write 41 frames")
+
+	data := buf.Bytes()
+	for i := 0; i < n; {
+		k := int(data[0])
+		require.Equal(t, min(100, n-i), k, "frame at index %d", i)
+		for j := 0; j < k; j++ {
+			require.Equal(t, uint16(i+j), binary.BigEndian.Uint16(data[1+2*j:]))
+		}
+		data, i = data[1+2*k:], i+k
+	}
+
+	t.Run("EncodeError", func(t *testing.T) {
+		t.Parallel()
+
+		errEncode := errors.New("encode failed")
+		var calls int
+		_, err := v.WriteChunked(&bytes.Buffer{}, 100, func(chunk []int) ([]byte, error) {
+			if calls++; calls == 3 {
+				return nil, errEncode
+			}
+			return frame(chunk)
+		})
+		assert.ErrorIs(t, err, errEncode)
+		assert.Contains(t, err.Error(), "index 200")
+		assert.Equal(t, 3, calls, "should stop at the first error")
+	})
+
+	t.Run("WriteError", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := v.WriteChunked(failWriter{}, 100, frame)
+		assert.ErrorIs(t, err, errWrite)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+
+		written, err := vector.Vector[int]{}.WriteChunked(failWriter{}, 100, frame)
+		assert.NoError(t, err, "should not write anything")
+		assert.Zero(t, written)
+	})
+
+	assert.Panics(t, func() { v.WriteChunked(&buf, 0, frame) })
+}
@@ -0,0 +1,77 @@
+package vector
+
+// COW is a copy-on-write wrapper around a Vector.  Reads are served from
+// the current version.  The first write after a snapshot forks a private
+// Builder from the shared Vector, and all subsequent writes are applied to
+// it in place until the next call to Snapshot.  This allows batches of
+// updates to be applied at Builder speed while still handing out cheap,
+// immutable snapshots.
+//
+// The zero value is an empty COW, ready to use.  A COW MUST NOT be used
+// from multiple goroutines concurrently; share the Vector returned by
+// Snapshot instead.
+type COW[T any] struct {
+	vec Vector[T]
+	b   *Builder[T]
+}
+
+// NewCOW returns a copy-on-write wrapper around v.
+func NewCOW[T any](v Vector[T]) *COW[T] {
+	return &COW[T]{vec: v}
+}
+
+// Len returns the number of elements in the current version.
+func (c *COW[T]) Len() int {
+	if c.b != nil {
+		return c.b.Len()
+	}
+
+	return c.vec.Len()
+}
+
+// At returns the ith element of the current version.
+func (c *COW[T]) At(i int) T {
+	if c.b != nil {
+		return c.b.Vector().At(i)
+	}
+
+	return c.vec.At(i)
+}
+
+// Set assigns t to the index.  As with Vector.Set, an index equal to
+// Len() appends t.
+func (c *COW[T]) Set(index int, t T) {
+	c.builder().Set(index, t)
+}
+
+// Append values to the current version.
+func (c *COW[T]) Append(ts ...T) {
+	if len(ts) > 0 {
+		c.builder().Append(ts...)
+	}
+}
+
+// Pop removes the last element of the current version.
+func (c *COW[T]) Pop() {
+	if c.Len() > 0 {
+		c.builder().Pop()
+	}
+}
+
+// Snapshot returns the current version as an immutable Vector.  The next
+// write will fork a new Builder, leaving the snapshot untouched.
+func (c *COW[T]) Snapshot() Vector[T] {
+	if c.b != nil {
+		c.vec, c.b = c.b.Vector(), nil
+	}
+
+	return c.vec
+}
+
+func (c *COW[T]) builder() *Builder[T] {
+	if c.b == nil {
+		c.b = c.vec.transient()
+	}
+
+	return c.b
+}
@@ -0,0 +1,107 @@
+package vector_test
+
+import (
+	"testing"
+
+	"github.com/lthibault/vector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCOW(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	var c vector.COW[int]
+	assert.Zero(t, c.Len(), "zero-value COW should be empty")
+
+	for i := 0; i < n; i++ {
+		c.Append(i)
+	}
+
+	s1 := c.Snapshot()
+	require.Equal(t, n, s1.Len(), "snapshot should contain %d elements", n)
+
+	t.Run("Set", func(t *testing.T) {
+		for i := 0; i < n; i++ {
+			c.Set(i, -i)
+		}
+
+		for i := 0; i < n; i++ {
+			require.Equal(t, -i, c.At(i), "should read own writes")
+			require.Equal(t, i, s1.At(i), "should not mutate snapshot")
+		}
+	})
+
+	s2 := c.Snapshot()
+
+	t.Run("Pop", func(t *testing.T) {
+		for i := n - 1; i >= n/2; i-- {
+			c.Pop()
+			require.Equal(t, i, c.Len())
+		}
+
+		require.Equal(t, n, s2.Len(), "should not shrink snapshot")
+		for i := 0; i < n; i++ {
+			require.Equal(t, -i, s2.At(i), "should not mutate snapshot")
+		}
+	})
+
+	t.Run("Append", func(t *testing.T) {
+		for i := n / 2; i < n; i++ {
+			c.Append(i)
+		}
+
+		s3 := c.Snapshot()
+		require.Equal(t, n, s3.Len())
+		for i := 0; i < n; i++ {
+			want := -i
+			if i >= n/2 {
+				want = i
+			}
+			require.Equal(t, want, s3.At(i))
+			require.Equal(t, -i, s2.At(i), "should not mutate snapshot")
+		}
+	})
+
+	t.Run("Pristine", func(t *testing.T) {
+		s := c.Snapshot()
+		assert.Equal(t, s, c.Snapshot(), "snapshot without writes should be stable")
+	})
+}
+
+func TestBuilderSetPop(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i
+	}
+
+	v := vector.New(is...)
+
+	// transient shares structure with v; it must not mutate it
+	c := vector.NewCOW(v)
+	for i := 0; i < n; i++ {
+		c.Set(i, 0)
+	}
+	for c.Len() > 0 {
+		c.Pop()
+	}
+	c.Append(-1, -2, -3)
+
+	require.Equal(t, n, v.Len(), "should not mutate source vector")
+	for i := 0; i < n; i++ {
+		require.Equal(t, i, v.At(i), "should not mutate source vector")
+	}
+
+	s := c.Snapshot()
+	require.Equal(t, 3, s.Len())
+	assert.Equal(t, -3, s.At(2))
+
+	assert.Panics(t, func() { c.Set(4, 0) }, "should panic when out of bounds")
+	assert.Panics(t, func() { c.Set(-1, 0) }, "should panic when out of bounds")
+}
@@ -0,0 +1,32 @@
+package vector
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// WriteCSV writes each element of v to w as a CSV record, as given by row.
+// If header is non-empty, it is written as the first record.  WriteCSV
+// stops at the first error.
+func WriteCSV[T any](w io.Writer, v Vector[T], row func(T) []string, header ...string) error {
+	cw := csv.NewWriter(w)
+
+	if len(header) > 0 {
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	v.forEach(func(_ int, t T) bool {
+		err = cw.Write(row(t))
+		return err == nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
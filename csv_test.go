@@ -0,0 +1,46 @@
+package vector_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/lthibault/vector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCSV(t *testing.T) {
+	t.Parallel()
+
+	type point struct{ X, Y int }
+
+	v := vector.New(point{1, 2}, point{3, 4}, point{5, 6})
+	row := func(p point) []string {
+		return []string{strconv.Itoa(p.X), strconv.Itoa(p.Y)}
+	}
+
+	t.Run("NoHeader", func(t *testing.T) {
+		var sb strings.Builder
+		require.NoError(t, vector.WriteCSV(&sb, v, row))
+		assert.Equal(t, "1,2\n3,4\n5,6\n", sb.String())
+	})
+
+	t.Run("Header", func(t *testing.T) {
+		var sb strings.Builder
+		require.NoError(t, vector.WriteCSV(&sb, v, row, "x", "y"))
+		assert.Equal(t, "x,y\n1,2\n3,4\n5,6\n", sb.String())
+	})
+
+	t.Run("WriteError", func(t *testing.T) {
+		err := vector.WriteCSV(failWriter{}, v, row)
+		assert.ErrorIs(t, err, errWrite, "should propagate write error")
+	})
+}
+
+var errWrite = errors.New("write failed")
+
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) { return 0, errWrite }
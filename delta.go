@@ -0,0 +1,50 @@
+package vector
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ErrCorruptDelta is returned by UnmarshalDelta when its input is not a
+// valid delta encoding.
+var ErrCorruptDelta = errors.New("corrupt delta encoding")
+
+// MarshalDelta encodes v compactly, as its first element followed by the
+// difference between each pair of successive elements.  Each value is
+// written as a zigzag-encoded varint, so that vectors whose successive
+// elements are close together, such as timestamps or sequential IDs, need
+// only a byte or two per element.
+func MarshalDelta(v Vector[int64]) []byte {
+	buf := make([]byte, 0, v.cnt)
+
+	var prev int64
+	v.forEach(func(_ int, x int64) bool {
+		buf = binary.AppendVarint(buf, x-prev) // wraps on overflow
+		prev = x
+		return true
+	})
+
+	return buf
+}
+
+// UnmarshalDelta decodes a vector encoded by MarshalDelta.  If data is
+// truncated or otherwise malformed, it returns an error wrapping
+// ErrCorruptDelta.
+func UnmarshalDelta(data []byte) (Vector[int64], error) {
+	b := NewBuilder[int64]()
+
+	var prev int64
+	for offset := 0; offset < len(data); {
+		delta, n := binary.Varint(data[offset:])
+		if n <= 0 {
+			return Vector[int64]{}, fmt.Errorf("%w: bad varint at offset %d", ErrCorruptDelta, offset)
+		}
+
+		prev += delta
+		b.Cons(prev)
+		offset += n
+	}
+
+	return b.Vector(), nil
+}
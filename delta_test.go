@@ -0,0 +1,58 @@
+package vector_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/lthibault/vector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelta(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	rng := rand.New(rand.NewSource(42))
+	b := vector.NewBuilder[int64]()
+	ts := int64(1_700_000_000_000)
+	for i := 0; i < n; i++ {
+		ts += rng.Int63n(100)
+		b.Append(ts)
+	}
+	v := b.Vector()
+
+	data := vector.MarshalDelta(v)
+	assert.Less(t, len(data), 2*n+8, "should encode small deltas compactly")
+
+	got, err := vector.UnmarshalDelta(data)
+	require.NoError(t, err)
+	assert.True(t, vector.Equal(v, got))
+
+	t.Run("Extremes", func(t *testing.T) {
+		t.Parallel()
+
+		v := vector.New[int64](math.MaxInt64, math.MinInt64, 0, -1, math.MaxInt64)
+		got, err := vector.UnmarshalDelta(vector.MarshalDelta(v))
+		require.NoError(t, err)
+		assert.True(t, vector.Equal(v, got), "should survive overflowing deltas")
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := vector.UnmarshalDelta(vector.MarshalDelta(vector.Vector[int64]{}))
+		require.NoError(t, err)
+		assert.Zero(t, got.Len())
+	})
+
+	t.Run("Corrupt", func(t *testing.T) {
+		t.Parallel()
+
+		// a continuation byte with nothing after it
+		_, err := vector.UnmarshalDelta(append(data[:len(data):len(data)], 0x80))
+		assert.ErrorIs(t, err, vector.ErrCorruptDelta)
+	})
+}
@@ -0,0 +1,168 @@
+package vector
+
+// Op identifies the kind of change described by an Edit.
+type Op uint8
+
+const (
+	// OpDelete removes an element from the source vector.
+	OpDelete Op = iota + 1
+	// OpInsert adds an element to the target vector.
+	OpInsert
+)
+
+func (op Op) String() string {
+	switch op {
+	case OpDelete:
+		return "delete"
+	case OpInsert:
+		return "insert"
+	default:
+		return "unknown"
+	}
+}
+
+// Edit is a single step in an edit script.  Indices refer to positions in
+// the original vectors:  the Index of an OpDelete is that of the removed
+// element in the source, and the Index of an OpInsert is that of the new
+// element in the target.  Value holds the inserted or deleted element.
+type Edit[T any] struct {
+	Op    Op
+	Index int
+	Value T
+}
+
+// Diff returns a minimal edit script that transforms a into b, ordered by
+// position.  It uses Myers' algorithm, which runs in O((N+M)D) time and
+// O(N+M+D^2) space, where D is the length of the resulting script.
+func Diff[T comparable](a, b Vector[T]) []Edit[T] {
+	xs, ys := a.toSlice(), b.toSlice()
+
+	// elements shared at either end cannot be part of a minimal script
+	var prefix int
+	for prefix < len(xs) && prefix < len(ys) && xs[prefix] == ys[prefix] {
+		prefix++
+	}
+	xs, ys = xs[prefix:], ys[prefix:]
+
+	for len(xs) > 0 && len(ys) > 0 && xs[len(xs)-1] == ys[len(ys)-1] {
+		xs, ys = xs[:len(xs)-1], ys[:len(ys)-1]
+	}
+
+	edits := myers(xs, ys)
+	for i := range edits {
+		edits[i].Index += prefix
+	}
+
+	return edits
+}
+
+func myers[T comparable](a, b []T) []Edit[T] {
+	n, m := len(a), len(b)
+	if n+m == 0 {
+		return nil
+	}
+
+	// v[off+k] holds the furthest x reached on diagonal k = x - y.  For
+	// backtracking, trace[d] records the diagonals -d..d of v at the start
+	// of round d; round d reads no others, so trace takes O(D^2) space.
+	off := n + m
+	v := make([]int, 2*off+2)
+	var trace [][]int
+
+search:
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v[off-d:off+d+1]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1] // move down
+			} else {
+				x = v[off+k-1] + 1 // move right
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[off+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	edits := make([]Edit[T], 0, len(trace)-1)
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d] // v[d+k] is diagonal k
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[d+k-1] < v[d+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		prevX := v[d+prevK]
+		prevY := prevX - prevK
+
+		if prevK == k+1 {
+			edits = append(edits, Edit[T]{Op: OpInsert, Index: prevY, Value: b[prevY]})
+		} else {
+			edits = append(edits, Edit[T]{Op: OpDelete, Index: prevX, Value: a[prevX]})
+		}
+
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+
+	return edits
+}
+
+// ApplyEdits returns the result of applying an edit script, such as the
+// one produced by Diff, to v.  Edits MUST be ordered by position:  the
+// indices of deletes must be strictly increasing positions in v, and those
+// of inserts strictly increasing positions in the result.  The Value of a
+// delete is ignored.  ApplyEdits panics if the script is invalid for v.
+func (v Vector[T]) ApplyEdits(edits []Edit[T]) Vector[T] {
+	if len(edits) == 0 {
+		return v
+	}
+
+	b := NewBuilder[T]()
+	var i int // next unread index in v
+	for _, e := range edits {
+		switch e.Op {
+		case OpDelete:
+			if e.Index < i || e.Index >= v.cnt {
+				panic("invalid delete index")
+			}
+
+			v.forRange(i, e.Index, b.cons)
+			i = e.Index + 1
+
+		case OpInsert:
+			n := e.Index - b.cnt // elements to copy before inserting
+			if n < 0 || i+n > v.cnt {
+				panic("invalid insert index")
+			}
+
+			v.forRange(i, i+n, b.cons)
+			b.Cons(e.Value)
+			i += n
+
+		default:
+			panic("invalid edit op")
+		}
+	}
+
+	v.forRange(i, v.cnt, b.cons)
+	return b.Vector()
+}
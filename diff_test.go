@@ -0,0 +1,145 @@
+package vector_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/lthibault/vector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Identical", func(t *testing.T) {
+		v := vector.New(1, 2, 3)
+		assert.Empty(t, vector.Diff(v, v), "identical vectors should have no edits")
+		assert.Empty(t, vector.Diff(vector.Vector[int]{}, vector.Vector[int]{}),
+			"empty vectors should have no edits")
+	})
+
+	t.Run("Simple", func(t *testing.T) {
+		a := vector.New([]rune("ABCABBA")...)
+		b := vector.New([]rune("CBABAC")...)
+
+		edits := vector.Diff(a, b)
+		assert.Len(t, edits, 5, "should produce minimal script")
+		assert.Equal(t, []rune("CBABAC"), applyEdits(t, a, edits))
+	})
+
+	t.Run("Random", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(42))
+		for i := 0; i < 100; i++ {
+			xs, ys := randomRunes(rng, 40), randomRunes(rng, 40)
+			a, b := vector.New(xs...), vector.New(ys...)
+
+			edits := vector.Diff(a, b)
+			require.Equal(t, ys, applyEdits(t, a, edits), "script should transform a into b")
+			require.Equal(t, len(xs)+len(ys)-2*lcs(xs, ys), len(edits),
+				"script should be minimal")
+		}
+	})
+}
+
+// applyEdits replays an edit script, checking that each edit references
+// the expected element.
+func applyEdits[T comparable](t *testing.T, a vector.Vector[T], edits []vector.Edit[T]) []T {
+	t.Helper()
+
+	out := make([]T, 0, a.Len())
+	var ai int
+	for _, e := range edits {
+		switch e.Op {
+		case vector.OpDelete:
+			for ; ai < e.Index; ai++ {
+				out = append(out, a.At(ai))
+			}
+			require.Equal(t, a.At(ai), e.Value, "deleted value should match source")
+			ai++
+
+		case vector.OpInsert:
+			for len(out) < e.Index {
+				out = append(out, a.At(ai))
+				ai++
+			}
+			out = append(out, e.Value)
+		}
+	}
+
+	for ; ai < a.Len(); ai++ {
+		out = append(out, a.At(ai))
+	}
+
+	return out
+}
+
+func randomRunes(rng *rand.Rand, max int) []rune {
+	rs := make([]rune, rng.Intn(max))
+	for i := range rs {
+		rs[i] = 'A' + rune(rng.Intn(4))
+	}
+	return rs
+}
+
+func lcs[T comparable](a, b []T) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				dp[i][j] = dp[i-1][j-1] + 1
+			} else {
+				dp[i][j] = max(dp[i-1][j], dp[i][j-1])
+			}
+		}
+	}
+
+	return dp[len(a)][len(b)]
+}
+
+func TestApplyEdits(t *testing.T) {
+	t.Parallel()
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(42))
+		for i := 0; i < 100; i++ {
+			a := vector.New(randomRunes(rng, 100)...)
+			b := vector.New(randomRunes(rng, 100)...)
+
+			got := a.ApplyEdits(vector.Diff(a, b))
+			require.True(t, vector.Equal(b, got), "should reconstruct target")
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		v := vector.New(1, 2, 3)
+		assert.Equal(t, v, v.ApplyEdits(nil), "empty script should no-op")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		v := vector.New(1, 2, 3)
+
+		assert.Panics(t, func() {
+			v.ApplyEdits([]vector.Edit[int]{{Op: vector.OpDelete, Index: 3}})
+		}, "should panic when deleting out of bounds")
+
+		assert.Panics(t, func() {
+			v.ApplyEdits([]vector.Edit[int]{
+				{Op: vector.OpDelete, Index: 1},
+				{Op: vector.OpDelete, Index: 0},
+			})
+		}, "should panic when deletes are out of order")
+
+		assert.Panics(t, func() {
+			v.ApplyEdits([]vector.Edit[int]{{Op: vector.OpInsert, Index: 5}})
+		}, "should panic when inserting past the end")
+
+		assert.Panics(t, func() {
+			v.ApplyEdits([]vector.Edit[int]{{Index: 0}})
+		}, "should panic on unknown op")
+	})
+}
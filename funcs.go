@@ -0,0 +1,813 @@
+package vector
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"reflect"
+)
+
+// TryMap returns a vector containing the result of applying f to each
+// element of v, in order.  If f returns an error, TryMap stops immediately
+// and returns the error, annotated with the index of the failing element.
+func TryMap[T, U any](v Vector[T], f func(T) (U, error)) (Vector[U], error) {
+	var err error
+	b := NewBuilder[U]()
+	v.forEach(func(i int, t T) bool {
+		var u U
+		if u, err = f(t); err != nil {
+			err = fmt.Errorf("index %d: %w", i, err)
+			return false
+		}
+
+		b.Cons(u)
+		return true
+	})
+
+	if err != nil {
+		return Vector[U]{}, err
+	}
+
+	return b.Vector(), nil
+}
+
+// MapFilter maps and filters v in a single pass.  For each element, f
+// returns a transformed value and whether to keep it; only kept values are
+// included in the result, in order.
+func MapFilter[T, U any](v Vector[T], f func(T) (U, bool)) Vector[U] {
+	b := NewBuilder[U]()
+	v.forEach(func(_ int, t T) bool {
+		if u, ok := f(t); ok {
+			b.Cons(u)
+		}
+
+		return true
+	})
+
+	return b.Vector()
+}
+
+// ReduceWhile folds the elements of v into an accumulator, starting from
+// init.  For each element, f returns the new accumulator and whether to
+// continue.  ReduceWhile returns the last accumulator returned by f, or
+// init if v is empty.
+func ReduceWhile[T, A any](v Vector[T], init A, f func(A, T) (A, bool)) A {
+	acc := init
+	v.forEach(func(_ int, t T) bool {
+		var more bool
+		acc, more = f(acc, t)
+		return more
+	})
+
+	return acc
+}
+
+// ReduceByKey folds the elements of v into one accumulator per key, in a
+// single pass.  Each key's accumulator starts out as init, and is updated
+// with f for every element that maps to that key.
+func ReduceByKey[T any, K comparable, A any](v Vector[T], key func(T) K, init A, f func(A, T) A) map[K]A {
+	m := make(map[K]A)
+	v.forEach(func(_ int, t T) bool {
+		k := key(t)
+		acc, ok := m[k]
+		if !ok {
+			acc = init
+		}
+
+		m[k] = f(acc, t)
+		return true
+	})
+
+	return m
+}
+
+// Equal reports whether a and b have the same length and contain equal
+// elements at every index.  Subtrees that are shared between a and b are
+// not traversed.
+func Equal[T comparable](a, b Vector[T]) bool {
+	if a.cnt != b.cnt {
+		return false
+	}
+
+	if a.root == b.root && a.tail == b.tail {
+		return true
+	}
+
+	for i := 0; i < a.cnt; i += width {
+		na, nb := a.nodeFor(i), b.nodeFor(i)
+		if na == nb {
+			continue
+		}
+
+		for j := 0; j < width && i+j < a.cnt; j++ {
+			x, _ := na.array[j].(T)
+			y, _ := nb.array[j].(T)
+			if x != y {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// EqualBy reports whether a and b have the same length, and elements with
+// equal keys at every index.  It stops at the first mismatch.
+func EqualBy[T any, K comparable](a, b Vector[T], key func(T) K) bool {
+	if a.cnt != b.cnt {
+		return false
+	}
+
+	return forEach2(a, b, func(_ int, x, y T) bool {
+		return key(x) == key(y)
+	})
+}
+
+// EqualNested reports whether two vectors of vectors have the same shape
+// and contain equal elements, as defined by Equal.
+func EqualNested[T comparable](a, b Vector[Vector[T]]) bool {
+	if a.cnt != b.cnt {
+		return false
+	}
+
+	for i := 0; i < a.cnt; i++ {
+		if !Equal(a.At(i), b.At(i)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Indices returns the indices of all elements in v that satisfy pred, in
+// ascending order.
+func Indices[T any](v Vector[T], pred func(T) bool) Vector[int] {
+	b := NewBuilder[int]()
+	v.forEach(func(i int, t T) bool {
+		if pred(t) {
+			b.Cons(i)
+		}
+
+		return true
+	})
+
+	return b.Vector()
+}
+
+// FoldColumns treats vs as the columns of a matrix and folds each row into
+// a separate accumulator, starting from init.  The slice passed to f holds
+// the row's elements in column order, and is reused between calls.  It
+// panics if the vectors do not all have the same length.
+func FoldColumns[T, A any](vs []Vector[T], init A, f func(A, []T) A) Vector[A] {
+	if len(vs) == 0 {
+		return Vector[A]{}
+	}
+
+	rows := vs[0].cnt
+	for _, v := range vs[1:] {
+		if v.cnt != rows {
+			panic("length mismatch")
+		}
+	}
+
+	b := NewBuilder[A]()
+	row := make([]T, len(vs))
+	leaves := make([]*node[T], len(vs))
+	for i := 0; i < rows; i++ {
+		if i&mask == 0 {
+			for j, v := range vs {
+				leaves[j] = v.nodeFor(i)
+			}
+		}
+
+		for j, n := range leaves {
+			row[j], _ = n.array[i&mask].(T)
+		}
+
+		b.Cons(f(init, row))
+	}
+
+	return b.Vector()
+}
+
+// Transpose returns the transpose of the matrix whose rows are the elements
+// of rows:  the jth element of the ith row becomes the ith element of the
+// jth row of the result.  It panics if the rows do not all have the same
+// length.
+func Transpose[T any](rows Vector[Vector[T]]) Vector[Vector[T]] {
+	rs := rows.toSlice()
+	if len(rs) == 0 {
+		return Vector[Vector[T]]{}
+	}
+
+	for _, r := range rs[1:] {
+		if r.cnt != rs[0].cnt {
+			panic("length mismatch")
+		}
+	}
+
+	cols := make([]*Builder[T], rs[0].cnt)
+	for j := range cols {
+		cols[j] = NewBuilder[T]()
+	}
+
+	for _, r := range rs {
+		r.forEach(func(j int, t T) bool {
+			cols[j].Cons(t)
+			return true
+		})
+	}
+
+	out := NewBuilder[Vector[T]]()
+	for _, c := range cols {
+		out.Cons(c.Vector())
+	}
+
+	return out.Vector()
+}
+
+// Coalesce returns a vector as long as the longest of vs, whose ith
+// element is the first non-zero ith element among vs, in argument order.
+// The zero value is treated as missing, as are indices beyond the end of a
+// shorter vector.  If every vector is missing an index, the result holds
+// the zero value there.
+func Coalesce[T comparable](vs ...Vector[T]) Vector[T] {
+	var n int
+	for _, v := range vs {
+		n = max(n, v.cnt)
+	}
+
+	var zero T
+	b := NewBuilder[T]()
+	leaves := make([]*node[T], len(vs))
+	for i := 0; i < n; i++ {
+		if i&mask == 0 {
+			for j, v := range vs {
+				leaves[j] = nil
+				if i < v.cnt {
+					leaves[j] = v.nodeFor(i)
+				}
+			}
+		}
+
+		t := zero
+		for j, leaf := range leaves {
+			if i >= vs[j].cnt {
+				continue
+			}
+
+			if t, _ = leaf.array[i&mask].(T); t != zero {
+				break
+			}
+		}
+
+		b.Cons(t)
+	}
+
+	return b.Vector()
+}
+
+// ToSet returns the set of distinct elements of v.
+func ToSet[T comparable](v Vector[T]) map[T]struct{} {
+	set := make(map[T]struct{})
+	v.forEach(func(_ int, t T) bool {
+		set[t] = struct{}{}
+		return true
+	})
+
+	return set
+}
+
+// FoldChunks folds each consecutive chunk of size elements of v into its
+// own accumulator, seeded by a fresh call to init, and returns the
+// accumulators in order.  The last chunk may be shorter.  It panics if
+// size <= 0.
+func FoldChunks[T, A any](v Vector[T], size int, init func() A, f func(A, T) A) Vector[A] {
+	if size <= 0 {
+		panic("non-positive chunk size")
+	}
+
+	var acc A
+	b := NewBuilder[A]()
+	v.forEach(func(i int, t T) bool {
+		if i%size == 0 {
+			acc = init()
+		}
+
+		if acc = f(acc, t); i%size == size-1 || i == v.cnt-1 {
+			b.Cons(acc)
+		}
+
+		return true
+	})
+
+	return b.Vector()
+}
+
+// Histogram counts the occurrences of each distinct element in v.
+func Histogram[T comparable](v Vector[T]) map[T]int {
+	return HistogramBy(v, func(t T) T { return t })
+}
+
+// HistogramBy counts the elements of v that map to each distinct key.
+func HistogramBy[T any, K comparable](v Vector[T], key func(T) K) map[K]int {
+	m := make(map[K]int)
+	v.forEach(func(_ int, t T) bool {
+		m[key(t)]++
+		return true
+	})
+
+	return m
+}
+
+// DistinctBy returns a vector containing the first element of v for each
+// distinct key, in their original order.
+func DistinctBy[T any, K comparable](v Vector[T], key func(T) K) Vector[T] {
+	b := NewBuilder[T]()
+	seen := make(map[K]struct{})
+	v.forEach(func(_ int, t T) bool {
+		k := key(t)
+		if _, ok := seen[k]; !ok {
+			seen[k] = struct{}{}
+			b.Cons(t)
+		}
+
+		return true
+	})
+
+	return b.Vector()
+}
+
+// forEach2 walks a and b in lockstep, calling f on each pair of elements
+// up to the length of the shorter vector.  Iteration stops early if f
+// returns false.
+func forEach2[A, B any](a Vector[A], b Vector[B], f func(int, A, B) bool) bool {
+	n := min(a.cnt, b.cnt)
+	for i := 0; i < n; i += width {
+		na, nb := a.nodeFor(i), b.nodeFor(i)
+		for j := 0; j < width && i+j < n; j++ {
+			x, _ := na.array[j].(A)
+			y, _ := nb.array[j].(B)
+			if !f(i+j, x, y) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// BuildIndex returns an inverted index of v, mapping each distinct element
+// to the ascending list of indices at which it occurs.
+func BuildIndex[T comparable](v Vector[T]) map[T][]int {
+	m := make(map[T][]int)
+	v.forEach(func(i int, t T) bool {
+		m[t] = append(m[t], i)
+		return true
+	})
+
+	return m
+}
+
+// CollectChan receives values from ch until it is closed, and returns them
+// as a Vector in the order in which they were received.
+func CollectChan[T any](ch <-chan T) Vector[T] {
+	b := NewBuilder[T]()
+	for t := range ch {
+		b.Cons(t)
+	}
+
+	return b.Vector()
+}
+
+// CollectChanContext is like CollectChan, but stops early if ctx expires.
+// In that case, it returns the values received so far, along with the
+// context's error.
+func CollectChanContext[T any](ctx context.Context, ch <-chan T) (Vector[T], error) {
+	b := NewBuilder[T]()
+	for {
+		select {
+		case t, ok := <-ch:
+			if !ok {
+				return b.Vector(), nil
+			}
+
+			b.Cons(t)
+
+		case <-ctx.Done():
+			return b.Vector(), ctx.Err()
+		}
+	}
+}
+
+// CountDistinct returns the number of distinct elements in v.
+func CountDistinct[T comparable](v Vector[T]) int {
+	return CountDistinctBy(v, func(t T) T { return t })
+}
+
+// CountDistinctBy returns the number of distinct keys among the elements
+// of v.
+func CountDistinctBy[T any, K comparable](v Vector[T], key func(T) K) int {
+	seen := make(map[K]struct{})
+	v.forEach(func(_ int, t T) bool {
+		seen[key(t)] = struct{}{}
+		return true
+	})
+
+	return len(seen)
+}
+
+// LongestRun returns the start index and length of the longest run of
+// equal adjacent elements in v.  Ties are resolved in favor of the earliest
+// run.  It returns (0, 0) if v is empty.
+func LongestRun[T comparable](v Vector[T]) (start, length int) {
+	return LongestRunFunc(v, func(a, b T) bool { return a == b })
+}
+
+// LongestRunFunc is like LongestRun, but uses eq to compare elements.
+func LongestRunFunc[T any](v Vector[T], eq func(a, b T) bool) (start, length int) {
+	var prev T
+	var cur int // start of the current run
+	v.forEach(func(i int, t T) bool {
+		if i > 0 && !eq(prev, t) {
+			cur = i
+		}
+
+		if i-cur+1 > length {
+			start, length = cur, i-cur+1
+		}
+
+		prev = t
+		return true
+	})
+
+	return
+}
+
+// Range is the half-open interval of indices [Start, End).
+type Range struct {
+	Start, End int
+}
+
+// FindRanges returns the maximal ranges of consecutive indices whose
+// elements satisfy pred, in ascending order.
+func FindRanges[T any](v Vector[T], pred func(T) bool) []Range {
+	var rs []Range
+	start := -1 // start of the current range, if any
+	v.forEach(func(i int, t T) bool {
+		switch ok := pred(t); {
+		case ok && start < 0:
+			start = i
+		case !ok && start >= 0:
+			rs = append(rs, Range{Start: start, End: i})
+			start = -1
+		}
+
+		return true
+	})
+
+	if start >= 0 {
+		rs = append(rs, Range{Start: start, End: v.cnt})
+	}
+
+	return rs
+}
+
+// RunsBy yields each maximal run of consecutive elements of v that share
+// the same key, along with that key.  Runs are built one at a time as the
+// sequence is consumed, so that only the current run is held in memory.
+func RunsBy[T any, K comparable](v Vector[T], key func(T) K) iter.Seq2[K, Vector[T]] {
+	return func(yield func(K, Vector[T]) bool) {
+		var cur K
+		var run *Builder[T]
+		if !v.forEach(func(_ int, t T) bool {
+			k := key(t)
+			if run != nil && k != cur {
+				if !yield(cur, run.Vector()) {
+					return false
+				}
+				run = nil
+			}
+
+			if run == nil {
+				cur, run = k, NewBuilder[T]()
+			}
+
+			run.Cons(t)
+			return true
+		}) {
+			return
+		}
+
+		if run != nil {
+			yield(cur, run.Vector())
+		}
+	}
+}
+
+// FirstDifference returns the smallest index at which a and b hold
+// different elements, and true.  If no such index exists because one
+// vector is a prefix of the other, it returns the length of the shorter
+// vector and false.  Leaves shared by a and b are skipped.
+func FirstDifference[T comparable](a, b Vector[T]) (int, bool) {
+	return FirstDifferenceFunc(a, b, func(x, y T) bool { return x == y })
+}
+
+// FirstDifferenceFunc is like FirstDifference, but uses eq to compare
+// elements.
+func FirstDifferenceFunc[T any](a, b Vector[T], eq func(x, y T) bool) (int, bool) {
+	n := min(a.cnt, b.cnt)
+	for i := 0; i < n; i += width {
+		na, nb := a.nodeFor(i), b.nodeFor(i)
+		if na == nb {
+			continue
+		}
+
+		for j := 0; j < width && i+j < n; j++ {
+			x, _ := na.array[j].(T)
+			y, _ := nb.array[j].(T)
+			if !eq(x, y) {
+				return i + j, true
+			}
+		}
+	}
+
+	return n, false
+}
+
+// Iota returns the vector [start, start+1, ..., start+count-1].  If count
+// is not positive, it returns the zero-value Vector.
+func Iota(start, count int) Vector[int] {
+	if count <= 0 {
+		return Vector[int]{}
+	}
+
+	b := NewBuilder[int]()
+	b.AppendFunc(count, func(i int) int { return start + i })
+	return b.Vector()
+}
+
+// MapStateful maps each element of v to a new value, threading a state
+// through the transformation.  Elements are visited in order, and each call
+// to f receives the state returned by the previous call, starting with
+// init.
+func MapStateful[T, U, S any](v Vector[T], init S, f func(S, T) (S, U)) Vector[U] {
+	b := NewBuilder[U]()
+	state := init
+	v.forEach(func(_ int, t T) bool {
+		var u U
+		state, u = f(state, t)
+		b.Cons(u)
+		return true
+	})
+
+	return b.Vector()
+}
+
+// MapScalar returns a vector containing f(t, scalar) for each element t of
+// v, such as each element scaled by a constant factor.
+func MapScalar[T, U any](v Vector[T], scalar U, f func(T, U) T) Vector[T] {
+	b := NewBuilder[T]()
+	v.forEach(func(_ int, t T) bool {
+		b.Cons(f(t, scalar))
+		return true
+	})
+
+	return b.Vector()
+}
+
+// Pairwise applies f to each pair of adjacent elements of v, returning a
+// vector of v.Len()-1 results.  If v has fewer than two elements, it
+// returns the zero-value Vector.
+func Pairwise[T, U any](v Vector[T], f func(a, b T) U) Vector[U] {
+	if v.cnt < 2 {
+		return Vector[U]{}
+	}
+
+	var prev T
+	b := NewBuilder[U]()
+	v.forEach(func(i int, t T) bool {
+		if i > 0 {
+			b.Cons(f(prev, t))
+		}
+
+		prev = t
+		return true
+	})
+
+	return b.Vector()
+}
+
+// Pair holds two values of possibly different types.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// ZipLongest pairs the elements of a and b by index, up to the length of
+// the longer vector.  Once the shorter vector is exhausted, fillA or fillB
+// is used in its place.
+func ZipLongest[A, B any](a Vector[A], b Vector[B], fillA A, fillB B) Vector[Pair[A, B]] {
+	out := NewBuilder[Pair[A, B]]()
+	forEach2(a, b, func(_ int, x A, y B) bool {
+		out.Cons(Pair[A, B]{First: x, Second: y})
+		return true
+	})
+
+	a.forRange(min(a.cnt, b.cnt), a.cnt, func(_ int, x A) bool {
+		out.Cons(Pair[A, B]{First: x, Second: fillB})
+		return true
+	})
+
+	b.forRange(min(a.cnt, b.cnt), b.cnt, func(_ int, y B) bool {
+		out.Cons(Pair[A, B]{First: fillA, Second: y})
+		return true
+	})
+
+	return out.Vector()
+}
+
+// Unfold builds a vector by repeatedly applying f to a state, starting
+// with seed.  Each call returns the next element, the next state, and
+// whether the element should be kept.  Generation stops at the first call
+// that returns false, whose element is discarded.  Since the length of the
+// result is not known in advance, f MUST eventually return false.
+func Unfold[S, T any](seed S, f func(S) (T, S, bool)) Vector[T] {
+	b := NewBuilder[T]()
+	for {
+		t, next, ok := f(seed)
+		if !ok {
+			return b.Vector()
+		}
+
+		b.Cons(t)
+		seed = next
+	}
+}
+
+// SharedNodes returns the number of trie nodes, internal and leaf alike,
+// that a and b share by pointer identity.  It is a diagnostic for measuring
+// structural sharing between versions of a vector.
+func SharedNodes[T any](a, b Vector[T]) int {
+	seen := make(map[*node[T]]struct{})
+	a.walkNodes(func(n *node[T]) {
+		seen[n] = struct{}{}
+	})
+
+	var shared int
+	b.walkNodes(func(n *node[T]) {
+		if _, ok := seen[n]; ok {
+			delete(seen, n) // count each node once
+			shared++
+		}
+	})
+
+	return shared
+}
+
+// FillArray copies the elements of v into dst if, and only if, v.Len() ==
+// len(dst), and reports whether the copy took place.  It is intended for
+// checked conversions to fixed-size arrays, by passing a slice of the whole
+// array:
+//
+//	var sum [16]byte
+//	ok := vector.FillArray(v, sum[:])
+func FillArray[T any](v Vector[T], dst []T) bool {
+	if v.cnt != len(dst) {
+		return false
+	}
+
+	v.ReadAt(dst, 0)
+	return true
+}
+
+// IsPalindrome reports whether v reads the same forward and backward.
+// Empty and single-element vectors are palindromes.
+func IsPalindrome[T comparable](v Vector[T]) bool {
+	return IsPalindromeFunc(v, func(a, b T) bool { return a == b })
+}
+
+// IsPalindromeFunc is like IsPalindrome, but compares elements using eq.
+// It stops at the first mismatched pair.
+func IsPalindromeFunc[T any](v Vector[T], eq func(a, b T) bool) bool {
+	var back *node[T]
+	return v.forRange(0, v.cnt/2, func(i int, t T) bool {
+		j := v.cnt - 1 - i
+		if back == nil || j&mask == mask {
+			back = v.nodeFor(j) // entered a new leaf from the right
+		}
+
+		u, _ := back.array[j&mask].(T)
+		return eq(t, u)
+	})
+}
+
+// ReplaceN returns a copy of v with the first n occurrences of old replaced
+// by new.  If n < 0, there is no limit on the number of replacements.  If
+// no element is replaced, v is returned unchanged.
+func ReplaceN[T comparable](v Vector[T], old, new T, n int) Vector[T] {
+	if n == 0 {
+		return v
+	}
+
+	var b *Builder[T]
+	v.forEach(func(i int, t T) bool {
+		if t != old {
+			return true
+		}
+
+		if b == nil {
+			b = v.transient()
+		}
+
+		b.Set(i, new)
+		n--
+		return n != 0
+	})
+
+	if b == nil {
+		return v
+	}
+
+	return b.Vector()
+}
+
+// Rebase returns the elements that were appended to base to produce branch,
+// and true.  If branch is not an extension of base, it returns the zero
+// Vector and false.  Leaves shared by base and branch are known to be
+// equal and are not compared, so that checking a branch produced by
+// appending to base costs O(base.Len()/32) plus a comparison of base's
+// tail; other elements are compared as by Vector.Equals.
+func Rebase[T any](base, branch Vector[T]) (Vector[T], bool) {
+	if branch.cnt < base.cnt {
+		return Vector[T]{}, false
+	}
+
+	for i := 0; i < base.cnt; i += width {
+		a, b := base.nodeFor(i), branch.nodeFor(i)
+		if a == b {
+			continue
+		}
+
+		for j := 0; j < width && i+j < base.cnt; j++ {
+			if !reflect.DeepEqual(a.array[j], b.array[j]) {
+				return Vector[T]{}, false
+			}
+		}
+	}
+
+	return branch.Slice(base.cnt, branch.cnt), true
+}
+
+// CommonPrefixLen returns the length of the longest common prefix of a and
+// b.  Leaves shared by a and b are skipped, so vectors derived from one
+// another are compared quickly.
+func CommonPrefixLen[T comparable](a, b Vector[T]) int {
+	return CommonPrefixLenFunc(a, b, func(x, y T) bool { return x == y })
+}
+
+// CommonPrefixLenFunc is like CommonPrefixLen, but compares elements using
+// eq.  Elements in leaves shared by a and b are taken to be equal without
+// calling eq, so eq should be reflexive.
+func CommonPrefixLenFunc[T any](a, b Vector[T], eq func(x, y T) bool) int {
+	n, _ := FirstDifferenceFunc(a, b, eq)
+	return n
+}
+
+// CommonSuffixLen returns the length of the longest common suffix of a and
+// b.  As with CommonPrefixLen, leaves shared by a and b are skipped when
+// they hold elements at the same distance from the ends of both vectors.
+func CommonSuffixLen[T comparable](a, b Vector[T]) int {
+	return CommonSuffixLenFunc(a, b, func(x, y T) bool { return x == y })
+}
+
+// CommonSuffixLenFunc is like CommonSuffixLen, but compares elements using
+// eq.  It stops at the first mismatch.  Elements in leaves shared by a and
+// b are taken to be equal without calling eq, so eq should be reflexive.
+func CommonSuffixLenFunc[T any](a, b Vector[T], eq func(x, y T) bool) int {
+	n := min(a.cnt, b.cnt)
+
+	var la, lb *node[T] // leaves of a and b holding indices i and j
+	for k := 0; k < n; k++ {
+		i, j := a.cnt-1-k, b.cnt-1-k
+		if la == nil || i&mask == mask {
+			la = a.nodeFor(i)
+		}
+		if lb == nil || j&mask == mask {
+			lb = b.nodeFor(j)
+		}
+
+		if la == lb && i&mask == j&mask {
+			k += i & mask // the rest of the leaf is shared, too
+			continue
+		}
+
+		x, _ := la.array[i&mask].(T)
+		y, _ := lb.array[j&mask].(T)
+		if !eq(x, y) {
+			return k
+		}
+	}
+
+	return n
+}
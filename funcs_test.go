@@ -0,0 +1,993 @@
+package vector_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/lthibault/vector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTryMap(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	ss := make([]string, n)
+	for i := range ss {
+		ss[i] = strconv.Itoa(i)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		v, err := vector.TryMap(vector.New(ss...), strconv.Atoi)
+		require.NoError(t, err, "should map all elements")
+		require.Equal(t, n, v.Len(), "should contain %d elements", n)
+
+		for i := 0; i < n; i++ {
+			assert.Equal(t, i, v.At(i))
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		ss := append(ss[:100:100], "fail")
+
+		v, err := vector.TryMap(vector.New(ss...), strconv.Atoi)
+		require.Error(t, err, "should abort on first error")
+		assert.Zero(t, v, "should return zero-value vector")
+		assert.Contains(t, err.Error(), "index 100", "should report failing index")
+
+		var numErr *strconv.NumError
+		assert.True(t, errors.As(err, &numErr), "should wrap underlying error")
+	})
+}
+
+func TestMapFilter(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	ss := make([]string, n)
+	for i := range ss {
+		ss[i] = strconv.Itoa(i)
+		if i%3 == 0 {
+			ss[i] = "x" + ss[i]
+		}
+	}
+
+	v := vector.MapFilter(vector.New(ss...), func(s string) (int, bool) {
+		i, err := strconv.Atoi(s)
+		return i, err == nil
+	})
+
+	require.Equal(t, n-(n+2)/3, v.Len(), "should drop rejected elements")
+	j := 0
+	for i := 0; i < n; i++ {
+		if i%3 != 0 {
+			require.Equal(t, i, v.At(j), "should preserve order")
+			j++
+		}
+	}
+
+	none := vector.MapFilter(vector.New(ss...), func(string) (int, bool) { return 0, false })
+	assert.Zero(t, none.Len())
+}
+
+func TestReduceByKey(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i
+	}
+
+	parity := func(i int) bool { return i%2 == 0 }
+	sum := func(acc, i int) int { return acc + i }
+
+	m := vector.ReduceByKey(vector.New(is...), parity, 0, sum)
+	require.Len(t, m, 2, "should have one accumulator per key")
+	assert.Equal(t, n/2*(n-2)/2, m[true], "should sum even elements")
+	assert.Equal(t, n/2*n/2, m[false], "should sum odd elements")
+
+	count := func(acc, _ int) int { return acc + 1 }
+	m = vector.ReduceByKey(vector.New(is...), parity, 10, count)
+	assert.Equal(t, 10+n/2, m[true], "should seed each key with init")
+	assert.Equal(t, 10+n/2, m[false], "should seed each key with init")
+
+	m = vector.ReduceByKey(vector.Vector[int]{}, parity, 0, sum)
+	assert.Empty(t, m, "should return empty map for empty vector")
+}
+
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i
+	}
+
+	v := vector.New(is...)
+
+	assert.True(t, vector.Equal(v, v), "vector should equal itself")
+	assert.True(t, vector.Equal(v, vector.New(is...)),
+		"vectors with same elements should be equal")
+	assert.True(t, vector.Equal(vector.Vector[int]{}, vector.NewBuilder[int]().Vector()),
+		"empty vectors should be equal")
+
+	assert.False(t, vector.Equal(v, v.Pop()),
+		"vectors with different lengths should not be equal")
+	assert.False(t, vector.Equal(v, vector.New(is...).Set(100, -1)),
+		"vectors with different elements should not be equal")
+	assert.False(t, vector.Equal(v, vector.New(is...).Set(n-1, -1)),
+		"vectors with different tails should not be equal")
+}
+
+func TestEqualNested(t *testing.T) {
+	t.Parallel()
+
+	grid := func(rows, cols int) vector.Vector[vector.Vector[int]] {
+		b := vector.NewBuilder[vector.Vector[int]]()
+		for i := 0; i < rows; i++ {
+			row := vector.NewBuilder[int]()
+			for j := 0; j < cols; j++ {
+				row.Append(i*cols + j)
+			}
+			b.Append(row.Vector())
+		}
+		return b.Vector()
+	}
+
+	a := grid(64, 64)
+	assert.True(t, vector.EqualNested(a, grid(64, 64)), "grids should be equal")
+	assert.False(t, vector.EqualNested(a, grid(63, 64)),
+		"grids with different row counts should not be equal")
+	assert.False(t, vector.EqualNested(a, grid(64, 63)),
+		"grids with different column counts should not be equal")
+
+	b := grid(64, 64)
+	b = b.Set(10, b.At(10).Set(10, -1))
+	assert.False(t, vector.EqualNested(a, b),
+		"grids with different elements should not be equal")
+}
+
+func TestIndices(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = n - i
+	}
+
+	v := vector.New(is...)
+
+	idx := vector.Indices(v, func(i int) bool { return i%3 == 0 })
+	require.Equal(t, n/3, idx.Len(), "should find every third element")
+
+	for i := 0; i < idx.Len(); i++ {
+		j := idx.At(i)
+		assert.Zero(t, v.At(j)%3, "index %d should point to a match", j)
+		if i > 0 {
+			assert.Greater(t, j, idx.At(i-1), "indices should be ascending")
+		}
+	}
+
+	none := vector.Indices(v, func(int) bool { return false })
+	assert.Zero(t, none.Len(), "should be empty when nothing matches")
+}
+
+func TestFoldColumns(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	cols := make([]vector.Vector[int], 3)
+	for j := range cols {
+		b := vector.NewBuilder[int]()
+		for i := 0; i < n; i++ {
+			b.Append(i * (j + 1))
+		}
+		cols[j] = b.Vector()
+	}
+
+	sum := func(acc int, row []int) int {
+		for _, x := range row {
+			acc += x
+		}
+		return acc
+	}
+
+	v := vector.FoldColumns(cols, 1, sum)
+	require.Equal(t, n, v.Len(), "should produce one result per row")
+	for i := 0; i < n; i++ {
+		assert.Equal(t, 1+6*i, v.At(i), "should fold row %d", i)
+	}
+
+	assert.Zero(t, vector.FoldColumns(nil, 0, sum).Len(),
+		"should return empty vector when there are no columns")
+
+	assert.Panics(t, func() {
+		vector.FoldColumns([]vector.Vector[int]{cols[0], cols[1].Pop()}, 0, sum)
+	}, "should panic on length mismatch")
+}
+
+func TestHistogram(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i % 10
+	}
+
+	v := vector.New(is...)
+
+	h := vector.Histogram(v)
+	require.Len(t, h, 10, "should count each distinct element")
+	for i := 0; i < 10; i++ {
+		want := n / 10
+		if i < n%10 {
+			want++
+		}
+		assert.Equal(t, want, h[i], "count of %d", i)
+	}
+
+	hb := vector.HistogramBy(v, func(i int) bool { return i < 5 })
+	assert.Equal(t, map[bool]int{true: 2050, false: 2046}, hb)
+
+	assert.Empty(t, vector.Histogram(vector.Vector[int]{}),
+		"should return empty map for empty vector")
+}
+
+func TestDistinctBy(t *testing.T) {
+	t.Parallel()
+
+	type record struct {
+		ID   int
+		Tags []string // not comparable
+	}
+
+	const n = 4096
+
+	b := vector.NewBuilder[record]()
+	for i := 0; i < n; i++ {
+		b.Append(record{ID: i % 100, Tags: []string{strconv.Itoa(i)}})
+	}
+
+	v := vector.DistinctBy(b.Vector(), func(r record) int { return r.ID })
+	require.Equal(t, 100, v.Len(), "should keep one element per key")
+	for i := 0; i < v.Len(); i++ {
+		assert.Equal(t, i, v.At(i).ID, "should preserve order")
+		assert.Equal(t, []string{strconv.Itoa(i)}, v.At(i).Tags,
+			"should keep first occurrence")
+	}
+}
+
+func TestBuildIndex(t *testing.T) {
+	t.Parallel()
+
+	v := vector.New("the", "quick", "fox", "jumps", "over", "the", "lazy", "fox")
+
+	idx := vector.BuildIndex(v)
+	assert.Equal(t, map[string][]int{
+		"the":   {0, 5},
+		"quick": {1},
+		"fox":   {2, 7},
+		"jumps": {3},
+		"over":  {4},
+		"lazy":  {6},
+	}, idx)
+
+	assert.Empty(t, vector.BuildIndex(vector.Vector[string]{}),
+		"should return empty index for empty vector")
+}
+
+func TestCollectChan(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	produce := func(n int) <-chan int {
+		ch := make(chan int)
+		go func() {
+			defer close(ch)
+			for i := 0; i < n; i++ {
+				ch <- i
+			}
+		}()
+		return ch
+	}
+
+	t.Run("Closed", func(t *testing.T) {
+		v := vector.CollectChan(produce(n))
+		require.Equal(t, n, v.Len(), "should collect %d elements", n)
+		for i := 0; i < n; i++ {
+			require.Equal(t, i, v.At(i))
+		}
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		v, err := vector.CollectChanContext(context.Background(), produce(n))
+		require.NoError(t, err, "should drain channel")
+		assert.Equal(t, n, v.Len(), "should collect %d elements", n)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		ch := make(chan int)
+		go func() {
+			for i := 0; i < 10; i++ {
+				ch <- i
+			}
+			cancel() // never close ch
+		}()
+
+		v, err := vector.CollectChanContext(ctx, ch)
+		require.ErrorIs(t, err, context.Canceled, "should report cancellation")
+		assert.Equal(t, 10, v.Len(), "should return elements received so far")
+	})
+}
+
+func TestCountDistinct(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i % 100
+	}
+
+	v := vector.New(is...)
+	assert.Equal(t, 100, vector.CountDistinct(v))
+	assert.Equal(t, 2, vector.CountDistinctBy(v, func(i int) bool { return i < 50 }))
+	assert.Zero(t, vector.CountDistinct(vector.Vector[int]{}))
+}
+
+func TestLongestRun(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name          string
+		in            []int
+		start, length int
+	}{
+		{"Empty", nil, 0, 0},
+		{"Single", []int{1}, 0, 1},
+		{"Distinct", []int{1, 2, 3}, 0, 1},
+		{"Middle", []int{1, 2, 2, 2, 3, 3}, 1, 3},
+		{"End", []int{1, 2, 3, 3, 3, 3}, 2, 4},
+		{"Tie", []int{1, 1, 2, 2}, 0, 2},
+	} {
+		start, length := vector.LongestRun(vector.New(tt.in...))
+		assert.Equal(t, tt.start, start, tt.name)
+		assert.Equal(t, tt.length, length, tt.name)
+	}
+
+	const n = 4096
+
+	is := make([]int, n)
+	for i := 1000; i < 2500; i++ {
+		is[i] = 1
+	}
+
+	start, length := vector.LongestRun(vector.New(is...))
+	assert.Equal(t, 2500, start, "should find run spanning leaves")
+	assert.Equal(t, n-2500, length)
+
+	parity := func(a, b int) bool { return a%2 == b%2 }
+	start, length = vector.LongestRunFunc(vector.New(1, 2, 4, 6, 3, 5), parity)
+	assert.Equal(t, 1, start)
+	assert.Equal(t, 3, length)
+}
+
+func TestFirstDifference(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i
+	}
+
+	v := vector.New(is...)
+
+	i, ok := vector.FirstDifference(v, v.Set(3000, -1))
+	assert.True(t, ok, "should find difference")
+	assert.Equal(t, 3000, i)
+
+	i, ok = vector.FirstDifference(v, v.Set(n-1, -1))
+	assert.True(t, ok, "should find difference in tail")
+	assert.Equal(t, n-1, i)
+
+	i, ok = vector.FirstDifference(v, v)
+	assert.False(t, ok, "identical vectors should not differ")
+	assert.Equal(t, n, i)
+
+	i, ok = vector.FirstDifference(v.Slice(0, 100), v)
+	assert.False(t, ok, "prefix should not differ")
+	assert.Equal(t, 100, i, "should return length of shorter vector")
+
+	i, ok = vector.FirstDifferenceFunc(v, v.Update(10, func(x int) int { return x + 2 }),
+		func(x, y int) bool { return x%2 == y%2 })
+	assert.False(t, ok, "should compare using eq")
+	assert.Equal(t, n, i)
+}
+
+func TestIota(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	v := vector.Iota(-10, n)
+	require.Equal(t, n, v.Len())
+	for i := 0; i < n; i++ {
+		require.Equal(t, i-10, v.At(i))
+	}
+
+	assert.True(t, vector.Iota(5, 0).IsZero(), "should return zero value for count=0")
+	assert.True(t, vector.Iota(5, -1).IsZero(), "should return zero value for count<0")
+}
+
+func TestMapStateful(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	// running sum
+	sums := vector.MapStateful(vector.Iota(0, n), 0, func(acc, i int) (int, int) {
+		return acc + i, acc + i
+	})
+
+	require.Equal(t, n, sums.Len())
+	for i := 0; i < n; i++ {
+		require.Equal(t, i*(i+1)/2, sums.At(i))
+	}
+
+	// state and output types may differ
+	labels := vector.MapStateful(vector.New("a", "b", "a", "c"), map[string]int{},
+		func(seen map[string]int, s string) (map[string]int, string) {
+			seen[s]++
+			return seen, s + strconv.Itoa(seen[s])
+		})
+	assert.Equal(t, "a2", labels.At(2))
+	assert.Equal(t, "c1", labels.At(3))
+}
+
+func TestZipLongest(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	a := vector.Iota(0, n)
+	b := vector.MapStateful(vector.Iota(0, n/3), 0, func(s, i int) (int, string) {
+		return s, strconv.Itoa(i)
+	})
+
+	check := func(t *testing.T, got vector.Vector[vector.Pair[int, string]]) {
+		require.Equal(t, n, got.Len())
+		for i := 0; i < n; i++ {
+			want := vector.Pair[int, string]{First: i, Second: "-"}
+			if i < n/3 {
+				want.Second = strconv.Itoa(i)
+			}
+			require.Equal(t, want, got.At(i), "index %d", i)
+		}
+	}
+
+	t.Run("LongerFirst", func(t *testing.T) {
+		t.Parallel()
+		check(t, vector.ZipLongest(a, b, -1, "-"))
+	})
+
+	t.Run("LongerSecond", func(t *testing.T) {
+		t.Parallel()
+
+		got := vector.ZipLongest(b, a, "-", -1)
+		swapped := vector.MapStateful(got, 0, func(s int, p vector.Pair[string, int]) (int, vector.Pair[int, string]) {
+			return s, vector.Pair[int, string]{First: p.Second, Second: p.First}
+		})
+		check(t, swapped)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+
+		got := vector.ZipLongest(vector.Vector[int]{}, vector.Vector[string]{}, 0, "")
+		assert.Zero(t, got.Len())
+	})
+}
+
+func TestUnfold(t *testing.T) {
+	t.Parallel()
+
+	// Collatz sequence starting at 27, which has 112 terms
+	v := vector.Unfold(27, func(n int) (int, int, bool) {
+		switch {
+		case n == 0:
+			return 0, 0, false
+		case n == 1:
+			return 1, 0, true
+		case n%2 == 0:
+			return n, n / 2, true
+		default:
+			return n, 3*n + 1, true
+		}
+	})
+
+	require.Equal(t, 112, v.Len())
+	assert.Equal(t, 27, v.At(0))
+	assert.Equal(t, 82, v.At(1))
+	assert.Equal(t, 1, v.At(v.Len()-1))
+
+	empty := vector.Unfold(0, func(int) (int, int, bool) { return 0, 0, false })
+	assert.Zero(t, empty.Len())
+}
+
+func TestSharedNodes(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	// 4096 elements:  127 leaves and a full tail, under a root with four
+	// internal children.
+	v := vector.Iota(0, n)
+	require.Equal(t, 133, vector.SharedNodes(v, v))
+
+	// Set copies the path from the root to a single leaf.
+	assert.Equal(t, 130, vector.SharedNodes(v, v.Set(0, -1)))
+	assert.Equal(t, 130, vector.SharedNodes(v.Set(0, -1), v))
+
+	// Appending to a full tail pushes a copy of it into the trie, along with
+	// copies of the root and the rightmost internal node.
+	assert.Equal(t, 130, vector.SharedNodes(v, v.Append(n)))
+
+	assert.Zero(t, vector.SharedNodes(v, vector.Iota(0, n)),
+		"independently built vectors should share nothing")
+	assert.Zero(t, vector.SharedNodes(vector.Vector[int]{}, v))
+}
+
+func TestFillArray(t *testing.T) {
+	t.Parallel()
+
+	v := vector.New([]byte("0123456789abcdef")...)
+
+	var sum [16]byte
+	require.True(t, vector.FillArray(v, sum[:]))
+	assert.Equal(t, [16]byte([]byte("0123456789abcdef")), sum)
+
+	var short [8]byte
+	assert.False(t, vector.FillArray(v, short[:]))
+	assert.Zero(t, short, "should not copy on length mismatch")
+
+	assert.True(t, vector.FillArray(vector.Vector[byte]{}, nil))
+}
+
+func TestIsPalindrome(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	for _, length := range []int{0, 1, 2, 31, 32, 33, n - 1, n} {
+		is := make([]int, length)
+		for i := 0; i < length/2; i++ {
+			is[i], is[length-1-i] = i, i
+		}
+
+		v := vector.New(is...)
+		require.Equal(t, length, v.Len())
+		assert.True(t, vector.IsPalindrome(v), "length %d", length)
+
+		if length > 1 {
+			assert.False(t, vector.IsPalindrome(v.Set(length-1, -2)), "length %d", length)
+			assert.False(t, vector.IsPalindrome(v.Set(length/2-1, -2)), "length %d", length)
+		}
+	}
+
+	eq := func(a, b []int) bool { return len(a) == len(b) }
+	assert.True(t, vector.IsPalindromeFunc(vector.New([]int{1}, nil, []int{2}), eq))
+	assert.False(t, vector.IsPalindromeFunc(vector.New([]int{1}, nil, []int{}), eq))
+}
+
+func TestTranspose(t *testing.T) {
+	t.Parallel()
+
+	const m, n = 3, 100
+
+	b := vector.NewBuilder[vector.Vector[int]]()
+	for i := 0; i < m; i++ {
+		b.Append(vector.Iota(i*n, n))
+	}
+	rows := b.Vector()
+
+	cols := vector.Transpose(rows)
+	require.Equal(t, n, cols.Len())
+	for j := 0; j < n; j++ {
+		require.Equal(t, m, cols.At(j).Len())
+		for i := 0; i < m; i++ {
+			require.Equal(t, rows.At(i).At(j), cols.At(j).At(i))
+		}
+	}
+
+	assert.True(t, vector.EqualNested(rows, vector.Transpose(cols)),
+		"should be its own inverse")
+	assert.Zero(t, vector.Transpose(vector.Vector[vector.Vector[int]]{}).Len())
+	assert.Panics(t, func() {
+		vector.Transpose(rows.Append(vector.Iota(0, n-1)))
+	}, "should panic on ragged rows")
+}
+
+func TestReplaceN(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	// every tenth element is a sentinel
+	v := vector.MapStateful(vector.Iota(0, n), 0, func(s, i int) (int, int) {
+		if i%10 == 0 {
+			return s, -1
+		}
+		return s, i
+	})
+
+	got := vector.ReplaceN(v, -1, 0, 5)
+	for i := 0; i < n; i++ {
+		want := v.At(i)
+		if i%10 == 0 && i < 50 {
+			want = 0
+		}
+		require.Equal(t, want, got.At(i), "index %d", i)
+	}
+	assert.Equal(t, -1, v.At(0), "should not modify the original")
+
+	all := vector.ReplaceN(v, -1, 0, -1)
+	assert.Zero(t, vector.Histogram(all)[-1], "should replace every occurrence")
+
+	assert.Equal(t, vector.SharedNodes(v, v), vector.SharedNodes(v, vector.ReplaceN(v, -2, 0, -1)),
+		"should return the original when nothing matches")
+	assert.Equal(t, vector.SharedNodes(v, v), vector.SharedNodes(v, vector.ReplaceN(v, -1, 0, 0)),
+		"should return the original when n == 0")
+}
+
+func TestRunsBy(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	// runs of increasing length: 1, 2, 2, 3, 3, 3, ...
+	events := vector.NewBuilder[int]()
+	for k := 1; k <= 90; k++ {
+		for j := 0; j < k; j++ {
+			events.Append(k)
+		}
+	}
+	stream := events.Vector()
+	require.Equal(t, 90*91/2, stream.Len())
+
+	var keys []int
+	for k, run := range vector.RunsBy(stream, func(x int) int { return x }) {
+		require.Equal(t, k, run.Len(), "run %d", k)
+		require.Equal(t, k, run.At(0))
+		keys = append(keys, k)
+	}
+	require.Len(t, keys, 90)
+
+	t.Run("Break", func(t *testing.T) {
+		t.Parallel()
+
+		var runs int
+		for range vector.RunsBy(vector.Iota(0, n), func(i int) int { return i / 100 }) {
+			if runs++; runs == 3 {
+				break
+			}
+		}
+		assert.Equal(t, 3, runs)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+
+		for range vector.RunsBy(vector.Vector[int]{}, func(i int) int { return i }) {
+			t.Fatal("should not yield any runs")
+		}
+	})
+}
+
+func TestCoalesce(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	// a holds the multiples of 2, b the multiples of 3, and c everything,
+	// but c is shorter.
+	sparse := func(k, length int) vector.Vector[int] {
+		return vector.MapStateful(vector.Iota(0, length), 0, func(s, i int) (int, int) {
+			if i%k == 0 {
+				return s, k*n + i
+			}
+			return s, 0
+		})
+	}
+	a, b, c := sparse(2, n/2), sparse(3, n), sparse(1, n/4)
+
+	got := vector.Coalesce(a, b, c)
+	require.Equal(t, n, got.Len())
+	for i := 0; i < n; i++ {
+		var want int
+		switch {
+		case i < n/2 && i%2 == 0:
+			want = 2*n + i
+		case i%3 == 0:
+			want = 3*n + i
+		case i < n/4:
+			want = n + i
+		}
+		require.Equal(t, want, got.At(i), "index %d", i)
+	}
+
+	assert.Zero(t, vector.Coalesce[int]().Len())
+	assert.True(t, vector.Equal(b, vector.Coalesce(b)))
+}
+
+func TestPairwise(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	squares := vector.MapStateful(vector.Iota(0, n), 0, func(s, i int) (int, int) {
+		return s, i * i
+	})
+
+	diffs := vector.Pairwise(squares, func(a, b int) int { return b - a })
+	require.Equal(t, n-1, diffs.Len())
+	for i := 0; i < n-1; i++ {
+		require.Equal(t, 2*i+1, diffs.At(i))
+	}
+
+	labels := vector.Pairwise(vector.New("a", "b", "c"), func(a, b string) string { return a + b })
+	assert.True(t, vector.Equal(vector.New("ab", "bc"), labels))
+
+	assert.True(t, vector.Pairwise(vector.New(1), func(a, b int) int { return a }).IsZero())
+	assert.True(t, vector.Pairwise(vector.Vector[int]{}, func(a, b int) int { return a }).IsZero())
+}
+
+func TestFindRanges(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	// errors in [10, 20), [31, 33), and [n-5, n)
+	isErr := func(i int) bool {
+		return (i >= 10 && i < 20) || (i >= 31 && i < 33) || i >= n-5
+	}
+
+	got := vector.FindRanges(vector.Iota(0, n), isErr)
+	assert.Equal(t, []vector.Range{
+		{Start: 10, End: 20},
+		{Start: 31, End: 33},
+		{Start: n - 5, End: n},
+	}, got)
+
+	all := vector.FindRanges(vector.Iota(0, n), func(int) bool { return true })
+	assert.Equal(t, []vector.Range{{Start: 0, End: n}}, all)
+
+	assert.Empty(t, vector.FindRanges(vector.Iota(0, n), func(int) bool { return false }))
+	assert.Empty(t, vector.FindRanges(vector.Vector[int]{}, isErr))
+}
+
+func TestToSet(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	set := vector.ToSet(vector.MapStateful(vector.Iota(0, n), 0, func(s, i int) (int, int) {
+		return s, i % 100
+	}))
+
+	require.Len(t, set, 100, "duplicates should collapse")
+	for i := 0; i < 100; i++ {
+		assert.Contains(t, set, i)
+	}
+	assert.NotContains(t, set, 100)
+
+	assert.Empty(t, vector.ToSet(vector.Vector[string]{}))
+}
+
+func TestFoldChunks(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	sum := func(acc, i int) int { return acc + i }
+	zero := func() int { return 0 }
+
+	got := vector.FoldChunks(vector.Iota(0, n), 100, zero, sum)
+	require.Equal(t, 41, got.Len(), "should include the partial chunk")
+	for k := 0; k < got.Len(); k++ {
+		want := 0
+		for i := k * 100; i < min(n, (k+1)*100); i++ {
+			want += i
+		}
+		require.Equal(t, want, got.At(k), "chunk %d", k)
+	}
+
+	// each chunk must start from a fresh accumulator
+	pages := vector.FoldChunks(vector.Iota(0, 10), 4, func() []int { return nil },
+		func(acc []int, i int) []int { return append(acc, i) })
+	require.Equal(t, 3, pages.Len())
+	assert.Equal(t, []int{0, 1, 2, 3}, pages.At(0))
+	assert.Equal(t, []int{8, 9}, pages.At(2))
+
+	assert.Zero(t, vector.FoldChunks(vector.Vector[int]{}, 3, zero, sum).Len())
+	assert.Panics(t, func() { vector.FoldChunks(vector.Iota(0, 10), 0, zero, sum) })
+}
+
+func TestEqualBy(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	type record struct {
+		ID      int
+		Updated int64
+	}
+	id := func(r record) int { return r.ID }
+
+	mk := func(stamp int64) vector.Vector[record] {
+		b := vector.NewBuilder[record]()
+		for i := 0; i < n; i++ {
+			b.Append(record{ID: i, Updated: stamp + int64(i)})
+		}
+		return b.Vector()
+	}
+	a, b := mk(0), mk(1000)
+
+	assert.True(t, vector.EqualBy(a, b, id), "should ignore fields outside the key")
+	assert.False(t, a.Equals(b))
+
+	assert.False(t, vector.EqualBy(a, b.Set(n-1, record{ID: -1}), id))
+	assert.False(t, vector.EqualBy(a, b.Pop(), id), "should compare lengths")
+	assert.True(t, vector.EqualBy(vector.Vector[record]{}, vector.New[record](), id))
+}
+
+func TestReduceWhile(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	// accumulate sizes until the budget is exhausted
+	var calls int
+	got := vector.ReduceWhile(vector.Iota(0, n), 0, func(acc, i int) (int, bool) {
+		calls++
+		acc += i
+		return acc, acc < 1000
+	})
+
+	assert.Equal(t, 1035, got, "should include the final accumulator") // 0+1+...+45
+	assert.Equal(t, 46, calls, "should stop when f returns false")
+
+	total := vector.ReduceWhile(vector.Iota(0, n), 0, func(acc, i int) (int, bool) {
+		return acc + i, true
+	})
+	assert.Equal(t, n*(n-1)/2, total)
+
+	assert.Equal(t, 42, vector.ReduceWhile(vector.Vector[int]{}, 42, func(acc, i int) (int, bool) {
+		return acc + i, true
+	}), "should return init for empty vector")
+}
+
+func TestRebase(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	base := vector.Iota(0, n+10)
+	branch := base
+	for i := n + 10; i < 2*n; i++ {
+		branch = branch.Append(i)
+	}
+
+	delta, ok := vector.Rebase(base, branch)
+	require.True(t, ok, "branch should descend from base")
+	assert.True(t, vector.Equal(vector.Iota(n+10, n-10), delta))
+
+	replica, ok := vector.Rebase(vector.Vector[int]{}, branch)
+	require.True(t, ok, "every vector should descend from the empty vector")
+	assert.True(t, vector.Equal(branch, replica))
+
+	delta, ok = vector.Rebase(base, base)
+	require.True(t, ok)
+	assert.Zero(t, delta.Len())
+
+	_, ok = vector.Rebase(base, branch.Set(100, -1))
+	assert.False(t, ok, "should detect divergence in the trie")
+
+	_, ok = vector.Rebase(base, branch.Set(n+5, -1))
+	assert.False(t, ok, "should detect divergence in base's tail")
+
+	_, ok = vector.Rebase(branch, base)
+	assert.False(t, ok, "should reject a shorter branch")
+}
+
+func TestMapScalar(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	scaled := vector.MapScalar(vector.Iota(0, n), 3, func(x, k int) int { return x * k })
+	require.Equal(t, n, scaled.Len())
+	for i := 0; i < n; i++ {
+		require.Equal(t, 3*i, scaled.At(i))
+	}
+
+	repeated := vector.MapScalar(vector.New("a", "b"), 3, strings.Repeat)
+	assert.True(t, vector.Equal(vector.New("aaa", "bbb"), repeated))
+
+	assert.Zero(t, vector.MapScalar(vector.Vector[int]{}, 1, func(x, k int) int { return x }).Len())
+}
+
+func TestCommonPrefixSuffixLen(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	v := vector.Iota(0, n)
+
+	assert.Equal(t, n, vector.CommonPrefixLen(v, v))
+	assert.Equal(t, n, vector.CommonSuffixLen(v, v))
+
+	assert.Equal(t, 1000, vector.CommonPrefixLen(v, v.Set(1000, -1)))
+	assert.Equal(t, n-1001, vector.CommonSuffixLen(v, v.Set(1000, -1)))
+
+	assert.Equal(t, 100, vector.CommonPrefixLen(v, vector.Iota(0, 100)))
+	assert.Equal(t, 100, vector.CommonPrefixLen(vector.Iota(0, 100), v))
+
+	// suffixes at different offsets
+	tail := vector.Iota(n-333, 333)
+	assert.Equal(t, 333, vector.CommonSuffixLen(v, tail))
+	assert.Equal(t, 333, vector.CommonSuffixLen(tail, v))
+	assert.Equal(t, 33, vector.CommonSuffixLen(v, tail.Set(299, -1)))
+	assert.Zero(t, vector.CommonSuffixLen(v, v.Append(n)))
+
+	sameParity := func(x, y int) bool { return x%2 == y%2 }
+	assert.Equal(t, n, vector.CommonPrefixLenFunc(v, vector.Iota(2, n), sameParity))
+	assert.Equal(t, n, vector.CommonSuffixLenFunc(v, vector.Iota(2, n), sameParity))
+
+	assert.Zero(t, vector.CommonPrefixLen(vector.Vector[int]{}, v))
+	assert.Zero(t, vector.CommonSuffixLen(v, vector.Vector[int]{}))
+}
+
+func TestCommonPrefixSuffixLenNaN(t *testing.T) {
+	t.Parallel()
+
+	const n, nan = 4096, 1000
+
+	fs := make([]float64, n)
+	for i := range fs {
+		fs[i] = float64(i)
+	}
+	fs[nan] = math.NaN()
+
+	// Shared leaves are skipped in both directions, so a vector always
+	// matches itself in full.
+	v := vector.New(fs...)
+	assert.Equal(t, n, vector.CommonPrefixLen(v, v))
+	assert.Equal(t, n, vector.CommonSuffixLen(v, v))
+
+	// An independent copy shares no leaves, so both stop at the NaN.
+	w := vector.New(fs...)
+	assert.Equal(t, nan, vector.CommonPrefixLen(v, w))
+	assert.Equal(t, n-nan-1, vector.CommonSuffixLen(v, w))
+}
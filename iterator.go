@@ -0,0 +1,41 @@
+package vector
+
+// Iterator is an explicit, resumable cursor over the elements of a Vector,
+// for callers that need to store their position and advance it lazily,
+// which a range-over-func loop does not allow.  Since Vectors are
+// immutable, an Iterator sees the elements and length of the Vector as of
+// its creation.
+//
+// An Iterator is not safe for concurrent use.
+type Iterator[T any] struct {
+	vec  Vector[T]
+	i    int
+	leaf *node[T]
+}
+
+// Iter returns an Iterator positioned at the first element of v.
+func (v Vector[T]) Iter() *Iterator[T] {
+	return &Iterator[T]{vec: v}
+}
+
+// HasNext reports whether a call to Next will return an element.
+func (it *Iterator[T]) HasNext() bool {
+	return it.i < it.vec.cnt
+}
+
+// Next returns the next element and advances the Iterator.  The current
+// leaf is cached, so sequential calls cost O(1).  It panics if the
+// Iterator is exhausted.
+func (it *Iterator[T]) Next() T {
+	if !it.HasNext() {
+		panic("iterator exhausted")
+	}
+
+	if it.i&mask == 0 {
+		it.leaf = it.vec.nodeFor(it.i)
+	}
+
+	t, _ := it.leaf.array[it.i&mask].(T)
+	it.i++
+	return t
+}
@@ -0,0 +1,54 @@
+package vector_test
+
+import (
+	"testing"
+
+	"github.com/lthibault/vector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterator(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	v := vector.Iota(0, n)
+	it := v.Iter()
+
+	// advance across several "method boundaries"
+	next := func(k int) (out []int) {
+		for ; k > 0 && it.HasNext(); k-- {
+			out = append(out, it.Next())
+		}
+		return
+	}
+
+	var got []int
+	for it.HasNext() {
+		got = append(got, next(100)...)
+	}
+
+	require.Len(t, got, n)
+	for i, x := range got {
+		require.Equal(t, i, x)
+	}
+
+	assert.Panics(t, func() { it.Next() }, "should panic when exhausted")
+
+	t.Run("Snapshot", func(t *testing.T) {
+		t.Parallel()
+
+		v := vector.New(1, 2, 3)
+		it := v.Iter()
+		_ = v.Append(4).Set(0, -1)
+
+		var got []int
+		for it.HasNext() {
+			got = append(got, it.Next())
+		}
+		assert.Equal(t, []int{1, 2, 3}, got, "should see the vector as of its creation")
+	})
+
+	assert.False(t, vector.Vector[int]{}.Iter().HasNext())
+}
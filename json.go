@@ -0,0 +1,96 @@
+package vector
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// DelimError is returned when a JSON stream does not contain the array
+// delimiter that was expected at the given input offset.
+type DelimError struct {
+	Want   json.Delim
+	Got    json.Token
+	Offset int64
+}
+
+func (e *DelimError) Error() string {
+	return fmt.Sprintf("expected %q at offset %d, got %v", e.Want, e.Offset, e.Got)
+}
+
+// DecodeJSONArray reads a JSON array from dec, decoding it one element at a
+// time so that the encoded array is never held in memory in its entirety.
+// The next token in dec MUST be the opening '['.  If it is not, a
+// *DelimError is returned.
+func DecodeJSONArray[T any](dec *json.Decoder) (Vector[T], error) {
+	if err := expectDelim(dec, '['); err != nil {
+		return Vector[T]{}, err
+	}
+
+	b := NewBuilder[T]()
+	for dec.More() {
+		var t T
+		if err := dec.Decode(&t); err != nil {
+			return Vector[T]{}, fmt.Errorf("index %d: %w", b.Len(), err)
+		}
+
+		b.Cons(t)
+	}
+
+	if err := expectDelim(dec, ']'); err != nil {
+		return Vector[T]{}, err
+	}
+
+	return b.Vector(), nil
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	offset := dec.InputOffset()
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return &DelimError{Want: want, Got: tok, Offset: offset}
+	}
+
+	return nil
+}
+
+// EncodeJSON writes v to w as a JSON array, encoding one element at a time
+// so that the encoded array is never held in memory in its entirety.
+// Encoding stops at the first error.
+func (v Vector[T]) EncodeJSON(w io.Writer) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	var err error
+	v.forEach(func(i int, t T) bool {
+		if i > 0 {
+			if _, err = io.WriteString(w, ","); err != nil {
+				return false
+			}
+		}
+
+		// Unlike json.Encoder, json.Marshal does not terminate the value
+		// with a newline.
+		var b []byte
+		if b, err = json.Marshal(t); err != nil {
+			err = fmt.Errorf("index %d: %w", i, err)
+			return false
+		}
+
+		_, err = w.Write(b)
+		return err == nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "]")
+	return err
+}
@@ -0,0 +1,130 @@
+package vector_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/lthibault/vector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeJSONArray(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		const n = 4096
+
+		var sb strings.Builder
+		sb.WriteString("[")
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			sb.WriteString(strconv.Itoa(i))
+		}
+		sb.WriteString("] ")
+
+		dec := json.NewDecoder(strings.NewReader(sb.String()))
+		v, err := vector.DecodeJSONArray[int](dec)
+		require.NoError(t, err, "should decode array")
+		require.Equal(t, n, v.Len(), "should contain %d elements", n)
+
+		for i := 0; i < n; i++ {
+			assert.Equal(t, i, v.At(i))
+		}
+
+		assert.False(t, dec.More(), "should consume closing delimiter")
+	})
+
+	t.Run("Exact", func(t *testing.T) {
+		var sb strings.Builder
+		require.NoError(t, vector.New(0, 1, 2).EncodeJSON(&sb))
+		assert.Equal(t, "[0,1,2]", sb.String())
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		dec := json.NewDecoder(strings.NewReader("[]"))
+		v, err := vector.DecodeJSONArray[int](dec)
+		require.NoError(t, err, "should decode empty array")
+		assert.Zero(t, v.Len(), "should be empty")
+	})
+
+	t.Run("NotArray", func(t *testing.T) {
+		dec := json.NewDecoder(strings.NewReader(`{"foo": 1}`))
+		_, err := vector.DecodeJSONArray[int](dec)
+
+		var delimErr *vector.DelimError
+		require.True(t, errors.As(err, &delimErr), "should return *DelimError")
+		assert.Equal(t, json.Delim('['), delimErr.Want)
+		assert.Equal(t, json.Delim('{'), delimErr.Got)
+	})
+
+	t.Run("BadElement", func(t *testing.T) {
+		dec := json.NewDecoder(strings.NewReader(`[1, 2, "three"]`))
+		_, err := vector.DecodeJSONArray[int](dec)
+		require.Error(t, err, "should fail to decode string as int")
+		assert.Contains(t, err.Error(), "index 2", "should report failing index")
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		dec := json.NewDecoder(strings.NewReader(`[1, 2`))
+		_, err := vector.DecodeJSONArray[int](dec)
+		assert.Error(t, err, "should fail on truncated input")
+	})
+}
+
+func TestEncodeJSON(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		var sb strings.Builder
+		require.NoError(t, vector.New(is...).EncodeJSON(&sb))
+
+		want, err := json.Marshal(is)
+		require.NoError(t, err)
+		assert.Equal(t, string(want), sb.String(), "should match json.Marshal")
+
+		var got []int
+		require.NoError(t, json.Unmarshal([]byte(sb.String()), &got),
+			"should produce valid JSON")
+		assert.Equal(t, is, got)
+
+		v, err := vector.DecodeJSONArray[int](json.NewDecoder(strings.NewReader(sb.String())))
+		require.NoError(t, err, "should round-trip through DecodeJSONArray")
+		assert.True(t, vector.Equal(vector.New(is...), v))
+	})
+
+	t.Run("Exact", func(t *testing.T) {
+		var sb strings.Builder
+		require.NoError(t, vector.New(0, 1, 2).EncodeJSON(&sb))
+		assert.Equal(t, "[0,1,2]", sb.String())
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		var sb strings.Builder
+		require.NoError(t, vector.Vector[int]{}.EncodeJSON(&sb))
+		assert.JSONEq(t, "[]", sb.String())
+	})
+
+	t.Run("EncodeError", func(t *testing.T) {
+		var sb strings.Builder
+		err := vector.New(func() {}).EncodeJSON(&sb)
+		assert.Error(t, err, "should fail to encode func")
+	})
+
+	t.Run("WriteError", func(t *testing.T) {
+		err := vector.New(is...).EncodeJSON(failWriter{})
+		assert.ErrorIs(t, err, errWrite, "should propagate write error")
+	})
+}
@@ -0,0 +1,195 @@
+package vector
+
+import (
+	"cmp"
+	"math"
+)
+
+// Numeric is a constraint that permits any integer or floating-point type.
+type Numeric interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Dot returns the sum of the products of the corresponding elements of a
+// and b.  It panics if a and b have different lengths.
+func Dot[T Numeric](a, b Vector[T]) (sum T) {
+	checkSameLen(a, b)
+	forEach2(a, b, func(_ int, x, y T) bool {
+		sum += x * y
+		return true
+	})
+
+	return
+}
+
+// Add returns the element-wise sum of a and b.  It panics if a and b have
+// different lengths.
+func Add[T Numeric](a, b Vector[T]) Vector[T] {
+	return zipNumeric(a, b, func(x, y T) T { return x + y })
+}
+
+// Mul returns the element-wise product of a and b.  It panics if a and b
+// have different lengths.
+func Mul[T Numeric](a, b Vector[T]) Vector[T] {
+	return zipNumeric(a, b, func(x, y T) T { return x * y })
+}
+
+func zipNumeric[T Numeric](a, b Vector[T], f func(T, T) T) Vector[T] {
+	checkSameLen(a, b)
+
+	out := NewBuilder[T]()
+	forEach2(a, b, func(_ int, x, y T) bool {
+		out.Cons(f(x, y))
+		return true
+	})
+
+	return out.Vector()
+}
+
+func checkSameLen[A, B any](a Vector[A], b Vector[B]) {
+	if a.cnt != b.cnt {
+		panic("length mismatch")
+	}
+}
+
+// SlidingMax returns the maximum of each window of consecutive elements
+// in v, in O(n) time.  The result contains v.Len()-window+1 elements, and
+// is empty if window exceeds the length of v.  It panics if window <= 0.
+func SlidingMax[T cmp.Ordered](v Vector[T], window int) Vector[T] {
+	return slidingExtremum(v, window, func(a, b T) bool { return cmp.Less(b, a) })
+}
+
+// SlidingMin returns the minimum of each window of consecutive elements
+// in v, in O(n) time.  The result contains v.Len()-window+1 elements, and
+// is empty if window exceeds the length of v.  It panics if window <= 0.
+func SlidingMin[T cmp.Ordered](v Vector[T], window int) Vector[T] {
+	return slidingExtremum(v, window, cmp.Less[T])
+}
+
+// slidingExtremum maintains a monotonic deque of candidates, ordered such
+// that each entry dominates all entries behind it.  The front of the deque
+// is therefore the extremum of the current window.
+func slidingExtremum[T any](v Vector[T], window int, dominates func(a, b T) bool) Vector[T] {
+	if window <= 0 {
+		panic("non-positive window")
+	}
+
+	type entry struct {
+		i int
+		t T
+	}
+
+	var dq []entry
+	b := NewBuilder[T]()
+	v.forEach(func(i int, t T) bool {
+		for len(dq) > 0 && !dominates(dq[len(dq)-1].t, t) {
+			dq = dq[:len(dq)-1]
+		}
+		dq = append(dq, entry{i: i, t: t})
+
+		if dq[0].i <= i-window {
+			dq = dq[1:]
+		}
+
+		if i >= window-1 {
+			b.Cons(dq[0].t)
+		}
+
+		return true
+	})
+
+	return b.Vector()
+}
+
+// MovingAverage returns the mean of each window of consecutive elements in
+// v, in O(n) time.  The result contains v.Len()-window+1 elements, and is
+// empty if window exceeds the length of v.  It panics if window <= 0.
+//
+// The window's sum is maintained incrementally rather than recomputed for
+// each window.  To keep the rounding error of repeated additions and
+// subtractions from accumulating, the sum is compensated using Neumaier's
+// variant of Kahan summation.
+func MovingAverage(v Vector[float64], window int) Vector[float64] {
+	if window <= 0 {
+		panic("non-positive window")
+	}
+
+	if window > v.cnt {
+		return Vector[float64]{}
+	}
+
+	var sum, c float64 // running sum, and its compensation term
+	add := func(x float64) {
+		t := sum + x
+		if math.Abs(sum) >= math.Abs(x) {
+			c += (sum - t) + x
+		} else {
+			c += (x - t) + sum
+		}
+		sum = t
+	}
+
+	ring := make([]float64, window)
+	b := NewBuilder[float64]()
+	v.forEach(func(i int, x float64) bool {
+		if i >= window {
+			add(-ring[i%window])
+		}
+		ring[i%window] = x
+		add(x)
+
+		if i >= window-1 {
+			b.Cons((sum + c) / float64(window))
+		}
+
+		return true
+	})
+
+	return b.Vector()
+}
+
+// Clamp returns a vector in which each element of v is limited to the
+// closed interval [lo, hi].  If no element lies outside the interval, v is
+// returned unchanged.  It panics if lo > hi.
+func Clamp[T cmp.Ordered](v Vector[T], lo, hi T) Vector[T] {
+	if cmp.Less(hi, lo) {
+		panic("invalid clamp bounds")
+	}
+
+	return v.UpdateWhere(func(t T) bool {
+		return cmp.Less(t, lo) || cmp.Less(hi, t)
+	}, func(t T) T {
+		return min(max(t, lo), hi)
+	})
+}
+
+// CumMax returns a vector whose ith element is the maximum of the first
+// i+1 elements of v.
+func CumMax[T cmp.Ordered](v Vector[T]) Vector[T] {
+	return cumulative(v, func(a, b T) T { return max(a, b) })
+}
+
+// CumMin returns a vector whose ith element is the minimum of the first
+// i+1 elements of v.
+func CumMin[T cmp.Ordered](v Vector[T]) Vector[T] {
+	return cumulative(v, func(a, b T) T { return min(a, b) })
+}
+
+func cumulative[T any](v Vector[T], f func(a, b T) T) Vector[T] {
+	var acc T
+	b := NewBuilder[T]()
+	v.forEach(func(i int, t T) bool {
+		if i == 0 {
+			acc = t
+		} else {
+			acc = f(acc, t)
+		}
+
+		b.Cons(acc)
+		return true
+	})
+
+	return b.Vector()
+}
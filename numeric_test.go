@@ -0,0 +1,183 @@
+package vector_test
+
+import (
+	"testing"
+
+	"github.com/lthibault/vector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArithmetic(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i
+	}
+
+	a := vector.New(is...)
+	b := vector.New(is...).ReverseRange(0, n)
+
+	t.Run("Dot", func(t *testing.T) {
+		var want int
+		for i := 0; i < n; i++ {
+			want += i * (n - 1 - i)
+		}
+
+		assert.Equal(t, want, vector.Dot(a, b))
+		assert.Zero(t, vector.Dot(vector.Vector[float64]{}, vector.Vector[float64]{}),
+			"dot product of empty vectors should be zero")
+	})
+
+	t.Run("Add", func(t *testing.T) {
+		sum := vector.Add(a, b)
+		require.Equal(t, n, sum.Len())
+		for i := 0; i < n; i++ {
+			require.Equal(t, n-1, sum.At(i))
+		}
+	})
+
+	t.Run("Mul", func(t *testing.T) {
+		prod := vector.Mul(a, b)
+		require.Equal(t, n, prod.Len())
+		for i := 0; i < n; i++ {
+			require.Equal(t, i*(n-1-i), prod.At(i))
+		}
+	})
+
+	t.Run("LengthMismatch", func(t *testing.T) {
+		assert.Panics(t, func() { vector.Dot(a, b.Pop()) },
+			"should panic on length mismatch")
+		assert.Panics(t, func() { vector.Add(a, b.Pop()) },
+			"should panic on length mismatch")
+		assert.Panics(t, func() { vector.Mul(a, b.Pop()) },
+			"should panic on length mismatch")
+	})
+}
+
+func TestSlidingExtremum(t *testing.T) {
+	t.Parallel()
+
+	const n, window = 4096, 17
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = (i * 7919) % 1000 // pseudo-random
+	}
+
+	v := vector.New(is...)
+
+	maxs := vector.SlidingMax(v, window)
+	mins := vector.SlidingMin(v, window)
+	require.Equal(t, n-window+1, maxs.Len(), "should have one max per window")
+	require.Equal(t, n-window+1, mins.Len(), "should have one min per window")
+
+	for i := 0; i+window <= n; i++ {
+		lo, hi := is[i], is[i]
+		for _, x := range is[i : i+window] {
+			lo, hi = min(lo, x), max(hi, x)
+		}
+
+		require.Equal(t, hi, maxs.At(i), "max of window %d", i)
+		require.Equal(t, lo, mins.At(i), "min of window %d", i)
+	}
+
+	assert.Equal(t, n, vector.SlidingMax(v, 1).Len(), "unit window should be identity")
+	assert.Zero(t, vector.SlidingMax(v, n+1).Len(),
+		"window larger than vector should yield empty vector")
+	assert.Panics(t, func() { vector.SlidingMin(v, 0) },
+		"should panic on non-positive window")
+}
+
+func TestMovingAverage(t *testing.T) {
+	t.Parallel()
+
+	const n, window = 4096, 10
+
+	fs := make([]float64, n)
+	for i := range fs {
+		fs[i] = float64(i)
+	}
+
+	avg := vector.MovingAverage(vector.New(fs...), window)
+	require.Equal(t, n-window+1, avg.Len(), "should have one mean per window")
+	for i := 0; i < avg.Len(); i++ {
+		require.InDelta(t, float64(i)+4.5, avg.At(i), 1e-9, "mean of window %d", i)
+	}
+
+	t.Run("Drift", func(t *testing.T) {
+		// large values followed by small ones defeat naive running sums
+		fs := make([]float64, 100000)
+		for i := range fs {
+			if i < len(fs)/2 {
+				fs[i] = 1e15 + 0.1
+			} else {
+				fs[i] = 0.1
+			}
+		}
+
+		avg := vector.MovingAverage(vector.New(fs...), 3)
+		assert.InDelta(t, 0.1, avg.At(avg.Len()-1), 1e-12, "should not drift")
+	})
+
+	assert.Zero(t, vector.MovingAverage(vector.New(1.0, 2.0), 3).Len(),
+		"window larger than vector should yield empty vector")
+	assert.Panics(t, func() { vector.MovingAverage(vector.New(1.0), 0) },
+		"should panic on non-positive window")
+}
+
+func TestClamp(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	v := vector.Iota(-n/2, n)
+	got := vector.Clamp(v, -10, 10)
+
+	require.Equal(t, n, got.Len())
+	for i := 0; i < n; i++ {
+		require.Equal(t, min(max(i-n/2, -10), 10), got.At(i))
+	}
+
+	t.Run("Unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, vector.Equal(v, vector.Clamp(v, -n, n)),
+			"should return the original vector when nothing is clamped")
+	})
+
+	t.Run("InvalidBounds", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Panics(t, func() { vector.Clamp(v, 1, 0) })
+	})
+}
+
+func TestCumulativeExtrema(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = (i * 7919) % 1009 // pseudo-random walk
+	}
+	v := vector.New(is...)
+
+	hi, lo := vector.CumMax(v), vector.CumMin(v)
+	require.Equal(t, n, hi.Len())
+	require.Equal(t, n, lo.Len())
+
+	maxSoFar, minSoFar := is[0], is[0]
+	for i, x := range is {
+		maxSoFar, minSoFar = max(maxSoFar, x), min(minSoFar, x)
+		require.Equal(t, maxSoFar, hi.At(i), "index %d", i)
+		require.Equal(t, minSoFar, lo.At(i), "index %d", i)
+	}
+
+	assert.Zero(t, vector.CumMax(vector.Vector[float64]{}).Len())
+	assert.True(t, vector.Equal(vector.New(-3.0), vector.CumMin(vector.New(-3.0))))
+}
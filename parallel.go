@@ -0,0 +1,112 @@
+package vector
+
+import "sync"
+
+// ParallelReduce folds v using up to the given number of goroutines.  The
+// vector is divided into contiguous, leaf-aligned ranges, each of which is
+// folded with accumulate, starting from identity.  The partial results are
+// then merged pairwise, in order, with combine.
+//
+// For the result to match a sequential fold, combine MUST be associative,
+// and identity MUST be an identity element for it.  If workers <= 1, the
+// fold is performed sequentially on the calling goroutine.
+func ParallelReduce[T, A any](v Vector[T], identity A, combine func(A, A) A, accumulate func(A, T) A, workers int) A {
+	fold := func(start, end int) A {
+		acc := identity
+		v.forRange(start, end, func(_ int, t T) bool {
+			acc = accumulate(acc, t)
+			return true
+		})
+
+		return acc
+	}
+
+	ranges := leafRanges(v.cnt, workers)
+	if len(ranges) <= 1 {
+		return fold(0, v.cnt)
+	}
+
+	partial := make([]A, len(ranges))
+
+	var wg sync.WaitGroup
+	for i, r := range ranges {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			partial[i] = fold(r[0], r[1])
+		}()
+	}
+	wg.Wait()
+
+	// combine adjacent results pairwise, preserving order
+	for len(partial) > 1 {
+		next := partial[:0]
+		for i := 0; i < len(partial); i += 2 {
+			if i+1 < len(partial) {
+				next = append(next, combine(partial[i], partial[i+1]))
+			} else {
+				next = append(next, partial[i])
+			}
+		}
+		partial = next
+	}
+
+	return partial[0]
+}
+
+// leafRanges divides [0, n) into at most parts contiguous ranges whose
+// boundaries fall on leaf boundaries.
+func leafRanges(n, parts int) [][2]int {
+	leaves := (n + width - 1) / width
+	parts = max(1, min(parts, leaves))
+	per := (leaves + parts - 1) / parts * width
+
+	ranges := make([][2]int, 0, parts)
+	for start := 0; start < n; start += per {
+		ranges = append(ranges, [2]int{start, min(start+per, n)})
+	}
+
+	return ranges
+}
+
+// MapChunks applies f to each leaf-aligned block of up to 32 elements of v,
+// using up to the given number of goroutines, and concatenates the results
+// in order.  f may return any number of elements for each block.  The slice
+// passed to f is reused between calls, and MUST NOT be retained.
+func MapChunks[T, U any](v Vector[T], f func([]T) []U, workers int) Vector[U] {
+	mapRange := func(start, end int) Vector[U] {
+		b := NewBuilder[U]()
+		chunk := make([]T, 0, width)
+		for i := start; i < end; i += width {
+			chunk = chunk[:min(width, end-i)]
+			v.ReadAt(chunk, i)
+			b.Append(f(chunk)...)
+		}
+
+		return b.Vector()
+	}
+
+	ranges := leafRanges(v.cnt, workers)
+	if len(ranges) <= 1 {
+		return mapRange(0, v.cnt)
+	}
+
+	parts := make([]Vector[U], len(ranges))
+
+	var wg sync.WaitGroup
+	for i, r := range ranges {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			parts[i] = mapRange(r[0], r[1])
+		}()
+	}
+	wg.Wait()
+
+	b := parts[0].transient()
+	for _, part := range parts[1:] {
+		part.forEach(b.cons)
+	}
+
+	return b.Vector()
+}
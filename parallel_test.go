@@ -0,0 +1,74 @@
+package vector_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/lthibault/vector"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallelReduce(t *testing.T) {
+	t.Parallel()
+
+	const n = 100000
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i
+	}
+
+	v := vector.New(is...)
+	add := func(a, b int) int { return a + b }
+
+	for _, workers := range []int{-1, 0, 1, 2, 3, 8, 1000, n} {
+		got := vector.ParallelReduce(v, 0, add, add, workers)
+		assert.Equal(t, n*(n-1)/2, got, "workers=%d", workers)
+	}
+
+	// string concatenation is associative but not commutative
+	small := vector.New(is[:500]...)
+	concat := func(a, b string) string { return a + b }
+	appendInt := func(a string, i int) string { return a + strconv.Itoa(i%10) }
+
+	want := vector.ParallelReduce(small, "", concat, appendInt, 1)
+	assert.Equal(t, want, vector.ParallelReduce(small, "", concat, appendInt, 7),
+		"should preserve element order")
+
+	assert.Zero(t, vector.ParallelReduce(vector.Vector[int]{}, 0, add, add, 4),
+		"should return identity for empty vector")
+}
+
+func TestMapChunks(t *testing.T) {
+	t.Parallel()
+
+	const n = 100000
+
+	v := vector.Iota(0, n)
+	double := func(chunk []int) []int {
+		out := make([]int, len(chunk))
+		for i, x := range chunk {
+			out[i] = 2 * x
+		}
+		return out
+	}
+
+	for _, workers := range []int{0, 1, 3, 8, n} {
+		got := vector.MapChunks(v, double, workers)
+		assert.Equal(t, n, got.Len(), "workers=%d", workers)
+		for i := 0; i < n; i += 997 {
+			assert.Equal(t, 2*i, got.At(i), "workers=%d", workers)
+		}
+		assert.Equal(t, 2*(n-1), got.At(n-1), "workers=%d", workers)
+	}
+
+	// blocks should be leaf-aligned, and results may vary in length
+	firsts := vector.MapChunks(v, func(chunk []int) []int {
+		return chunk[:1]
+	}, 4)
+	assert.Equal(t, (n+31)/32, firsts.Len())
+	assert.Equal(t, 32, firsts.At(1))
+
+	assert.Zero(t, vector.MapChunks(vector.Vector[int]{}, double, 4).Len(),
+		"empty input should yield empty result")
+}
@@ -0,0 +1,45 @@
+package vector
+
+// Queue is an immutable FIFO queue.  It is backed by two Vectors: elements
+// are enqueued onto the back, and dequeued from the front, which holds its
+// elements in reverse order.  When the front runs out, the back is reversed
+// to replace it, so that both operations run in amortized O(1) time.
+//
+// The amortized bound assumes that each version of the Queue is dequeued
+// from at most once, as when old versions are discarded.  A Dequeue that
+// finds the front empty reverses the whole back, in O(n) time, and
+// repeating it on the same saved version repeats the reversal; for
+// example, exploring several successors of one stored BFS state may cost
+// O(n) per Dequeue.
+//
+// The zero value is an empty Queue, ready to use.
+type Queue[T any] struct {
+	front, back Vector[T]
+}
+
+// Len returns the number of elements in the Queue.
+func (q Queue[T]) Len() int {
+	return q.front.Len() + q.back.Len()
+}
+
+// Enqueue returns a new Queue with t added to the back.
+func (q Queue[T]) Enqueue(t T) Queue[T] {
+	return Queue[T]{front: q.front, back: q.back.cons(t)}
+}
+
+// Dequeue returns the element at the front of the Queue, and a new Queue
+// without it.  If the Queue is empty, ok is false and the receiver is
+// returned unchanged.  It takes O(n) time when the front is empty; see
+// Queue for when this cost is amortized.
+func (q Queue[T]) Dequeue() (t T, rest Queue[T], ok bool) {
+	if q.front.cnt == 0 {
+		if q.back.cnt == 0 {
+			return t, q, false
+		}
+
+		q.front, q.back = q.back.Reverse(), Vector[T]{}
+	}
+
+	t = q.front.At(q.front.cnt - 1)
+	return t, Queue[T]{front: q.front.Pop(), back: q.back}, true
+}
@@ -0,0 +1,52 @@
+package vector_test
+
+import (
+	"testing"
+
+	"github.com/lthibault/vector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueue(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	var q vector.Queue[int]
+	_, rest, ok := q.Dequeue()
+	assert.False(t, ok, "dequeueing empty queue should fail")
+	assert.Zero(t, rest.Len())
+
+	for i := 0; i < n; i++ {
+		q = q.Enqueue(i)
+	}
+	require.Equal(t, n, q.Len(), "should contain %d elements", n)
+
+	snapshot := q
+
+	// interleave dequeues with enqueues
+	var x int
+	for i := 0; i < n/2; i++ {
+		x, q, ok = q.Dequeue()
+		require.True(t, ok)
+		require.Equal(t, i, x, "should dequeue in FIFO order")
+	}
+
+	for i := n; i < n+100; i++ {
+		q = q.Enqueue(i)
+	}
+
+	for i := n / 2; i < n+100; i++ {
+		x, q, ok = q.Dequeue()
+		require.True(t, ok)
+		require.Equal(t, i, x, "should dequeue in FIFO order")
+	}
+
+	assert.Zero(t, q.Len(), "should be empty")
+
+	x, _, ok = snapshot.Dequeue()
+	require.True(t, ok)
+	assert.Zero(t, x, "should not mutate earlier versions")
+	assert.Equal(t, n, snapshot.Len(), "should not mutate earlier versions")
+}
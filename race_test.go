@@ -0,0 +1,62 @@
+//go:build race
+
+package vector_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/lthibault/vector"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConcurrentDerivation checks that vectors can be shared between
+// goroutines.  Run with -race:  any write to a node reachable from the
+// shared base vector is reported as a data race.
+func TestConcurrentDerivation(t *testing.T) {
+	t.Parallel()
+
+	const n, workers = 4096 + 10, 16
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i
+	}
+
+	base := vector.New(is...)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			v := base
+			for i := 0; i < n; i += 7 {
+				assert.Equal(t, i, base.At(i))
+
+				v = v.Set(i, -w)
+				v = v.Update((i*31)%n, func(x int) int { return x + 1 })
+			}
+
+			for i := 0; i < 100; i++ {
+				base.Append(w)
+				base.Append(w, w, w)
+				base.Pop()
+				base.PopNValues(40)
+			}
+
+			c := vector.NewCOW(base)
+			c.Set(n/2, w)
+			c.Append(w)
+			c.Pop()
+			c.Pop()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, n, base.Len(), "base vector should not change length")
+	for i := 0; i < n; i++ {
+		assert.Equal(t, i, base.At(i), "base vector should not be mutated")
+	}
+}
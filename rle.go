@@ -0,0 +1,46 @@
+package vector
+
+// Run is a value repeated Count times in a row.
+type Run[T any] struct {
+	Value T
+	Count int
+}
+
+// RunLengthEncode collapses each maximal run of consecutive equal elements
+// of v into a single Run.
+func RunLengthEncode[T comparable](v Vector[T]) Vector[Run[T]] {
+	b := NewBuilder[Run[T]]()
+	var cur Run[T]
+	v.forEach(func(i int, t T) bool {
+		if i > 0 && t != cur.Value {
+			b.Cons(cur)
+			cur.Count = 0
+		}
+
+		cur.Value = t
+		cur.Count++
+		return true
+	})
+
+	if cur.Count > 0 {
+		b.Cons(cur)
+	}
+
+	return b.Vector()
+}
+
+// RunLengthDecode is the inverse of RunLengthEncode.  Runs whose Count is
+// not positive contribute no elements.  Full leaves within a long run share
+// a single node, so the decoded vector remains compact in memory.
+func RunLengthDecode[T any](runs Vector[Run[T]]) Vector[T] {
+	b := NewBuilder[T]()
+	runs.forEach(func(_ int, r Run[T]) bool {
+		if r.Count > 0 {
+			b.appendRepeat(r.Value, r.Count)
+		}
+
+		return true
+	})
+
+	return b.Vector()
+}
@@ -0,0 +1,55 @@
+package vector_test
+
+import (
+	"testing"
+
+	"github.com/lthibault/vector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunLength(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	b := vector.NewBuilder[string]()
+	for _, r := range []vector.Run[string]{
+		{Value: "a", Count: 1},
+		{Value: "b", Count: 31},
+		{Value: "a", Count: n},
+		{Value: "", Count: 100},
+		{Value: "c", Count: 33},
+	} {
+		for i := 0; i < r.Count; i++ {
+			b.Append(r.Value)
+		}
+	}
+	v := b.Vector()
+
+	runs := vector.RunLengthEncode(v)
+	require.Equal(t, 5, runs.Len())
+	assert.Equal(t, vector.Run[string]{Value: "a", Count: n}, runs.At(2))
+	assert.Equal(t, vector.Run[string]{Value: "", Count: 100}, runs.At(3))
+
+	assert.True(t, vector.Equal(v, vector.RunLengthDecode(runs)), "should round-trip")
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+
+		runs := vector.RunLengthEncode(vector.Vector[int]{})
+		assert.Zero(t, runs.Len())
+		assert.Zero(t, vector.RunLengthDecode(runs).Len())
+	})
+
+	t.Run("NonPositiveCount", func(t *testing.T) {
+		t.Parallel()
+
+		got := vector.RunLengthDecode(vector.New(
+			vector.Run[int]{Value: 1, Count: 2},
+			vector.Run[int]{Value: 2, Count: 0},
+			vector.Run[int]{Value: 3, Count: -1},
+		))
+		assert.True(t, vector.Equal(vector.New(1, 1), got))
+	})
+}
@@ -0,0 +1,187 @@
+package vector
+
+// Rope is an immutable sequence built by concatenating Vectors.  Rather
+// than copying elements, Concat and Slice rearrange a balanced binary tree
+// whose leaves are views onto Vectors, so both run in O(log n) time.  Use
+// Flatten to collapse a Rope back into a plain Vector.
+//
+// The zero value is an empty Rope, ready to use.
+type Rope[T any] struct {
+	root *ropeNode[T]
+}
+
+// ropeNode is either a leaf, holding the elements [off, off+len) of vec,
+// or a branch with exactly two children.  Branches are kept AVL-balanced.
+type ropeNode[T any] struct {
+	left, right *ropeNode[T]
+	vec         Vector[T]
+	off         int
+	len, depth  int
+}
+
+// NewRope returns a Rope containing the elements of v.
+func NewRope[T any](v Vector[T]) Rope[T] {
+	if v.cnt == 0 {
+		return Rope[T]{}
+	}
+
+	return Rope[T]{root: &ropeNode[T]{vec: v, len: v.cnt}}
+}
+
+// Len returns the number of elements contained in the Rope.
+func (r Rope[T]) Len() int {
+	if r.root == nil {
+		return 0
+	}
+
+	return r.root.len
+}
+
+// At returns the ith element in the Rope.
+func (r Rope[T]) At(i int) T {
+	if i < 0 || i >= r.Len() {
+		panic("index out of bounds")
+	}
+
+	n := r.root
+	for n.left != nil {
+		if i < n.left.len {
+			n = n.left
+		} else {
+			i -= n.left.len
+			n = n.right
+		}
+	}
+
+	return n.vec.At(n.off + i)
+}
+
+// Concat returns a Rope containing the elements of r followed by those of
+// other.
+func (r Rope[T]) Concat(other Rope[T]) Rope[T] {
+	return Rope[T]{root: joinRope(r.root, other.root)}
+}
+
+// Slice returns a Rope containing the elements in [start, end).  It
+// panics if 0 <= start <= end <= r.Len() does not hold.
+func (r Rope[T]) Slice(start, end int) Rope[T] {
+	if start < 0 || end < start || end > r.Len() {
+		panic("slice bounds out of range")
+	}
+
+	if start == end {
+		return Rope[T]{}
+	}
+
+	return Rope[T]{root: r.root.slice(start, end)}
+}
+
+// Flatten returns a Vector containing the elements of the Rope.
+func (r Rope[T]) Flatten() Vector[T] {
+	if r.root == nil {
+		return Vector[T]{}
+	}
+
+	if r.root.left == nil && r.root.off == 0 && r.root.len == r.root.vec.cnt {
+		return r.root.vec
+	}
+
+	b := NewBuilder[T]()
+	r.root.flatten(b)
+	return b.Vector()
+}
+
+func (n *ropeNode[T]) flatten(b *Builder[T]) {
+	if n.left == nil {
+		n.vec.forRange(n.off, n.off+n.len, b.cons)
+		return
+	}
+
+	n.left.flatten(b)
+	n.right.flatten(b)
+}
+
+func (n *ropeNode[T]) slice(start, end int) *ropeNode[T] {
+	if start == 0 && end == n.len {
+		return n
+	}
+
+	if n.left == nil {
+		return &ropeNode[T]{vec: n.vec, off: n.off + start, len: end - start}
+	}
+
+	split := n.left.len
+	switch {
+	case end <= split:
+		return n.left.slice(start, end)
+	case start >= split:
+		return n.right.slice(start-split, end-split)
+	default:
+		return joinRope(n.left.slice(start, split), n.right.slice(0, end-split))
+	}
+}
+
+// joinRope concatenates two balanced trees, descending the spine of the
+// deeper one until the heights are comparable and rebalancing on the way
+// back up.
+func joinRope[T any](l, r *ropeNode[T]) *ropeNode[T] {
+	switch {
+	case l == nil:
+		return r
+	case r == nil:
+		return l
+	case l.left == nil && r.left == nil && l.len+r.len <= width:
+		// merge small leaves so that repeated appends don't degenerate
+		// into a tree of single elements
+		b := NewBuilder[T]()
+		l.vec.forRange(l.off, l.off+l.len, b.cons)
+		r.vec.forRange(r.off, r.off+r.len, b.cons)
+		return &ropeNode[T]{vec: b.Vector(), len: b.cnt}
+	case l.depth > r.depth+1:
+		return rebalanceRope(newRopeBranch(l.left, joinRope(l.right, r)))
+	case r.depth > l.depth+1:
+		return rebalanceRope(newRopeBranch(joinRope(l, r.left), r.right))
+	default:
+		return newRopeBranch(l, r)
+	}
+}
+
+func newRopeBranch[T any](l, r *ropeNode[T]) *ropeNode[T] {
+	return &ropeNode[T]{
+		left:  l,
+		right: r,
+		len:   l.len + r.len,
+		depth: 1 + max(l.depth, r.depth),
+	}
+}
+
+func rebalanceRope[T any](n *ropeNode[T]) *ropeNode[T] {
+	switch {
+	case n.left.depth > n.right.depth+1:
+		l := n.left
+		if l.right.depth > l.left.depth {
+			l = rotateRopeLeft(l)
+		}
+		return rotateRopeRight(newRopeBranch(l, n.right))
+
+	case n.right.depth > n.left.depth+1:
+		r := n.right
+		if r.left.depth > r.right.depth {
+			r = rotateRopeRight(r)
+		}
+		return rotateRopeLeft(newRopeBranch(n.left, r))
+
+	default:
+		return n
+	}
+}
+
+func rotateRopeLeft[T any](n *ropeNode[T]) *ropeNode[T] {
+	r := n.right
+	return newRopeBranch(newRopeBranch(n.left, r.left), r.right)
+}
+
+func rotateRopeRight[T any](n *ropeNode[T]) *ropeNode[T] {
+	l := n.left
+	return newRopeBranch(l.left, newRopeBranch(l.right, n.right))
+}
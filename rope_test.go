@@ -0,0 +1,85 @@
+package vector_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/lthibault/vector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRope(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i
+	}
+
+	t.Run("ZeroValue", func(t *testing.T) {
+		var r vector.Rope[int]
+		assert.Zero(t, r.Len(), "zero-value rope should have zero length")
+		assert.True(t, r.Flatten().IsZero(), "should flatten to zero-value vector")
+	})
+
+	t.Run("Concat", func(t *testing.T) {
+		var r vector.Rope[int]
+		for i := 0; i < n; i += 100 {
+			chunk := vector.New(is[i:min(i+100, n)]...)
+			r = r.Concat(vector.NewRope(chunk))
+		}
+
+		require.Equal(t, n, r.Len(), "should contain %d elements", n)
+		for i := 0; i < n; i++ {
+			require.Equal(t, i, r.At(i))
+		}
+
+		assert.True(t, vector.Equal(vector.New(is...), r.Flatten()),
+			"should flatten to original elements")
+	})
+
+	t.Run("SmallConcat", func(t *testing.T) {
+		var r vector.Rope[int]
+		for i := 0; i < n; i++ {
+			r = r.Concat(vector.NewRope(vector.New(i)))
+		}
+
+		require.Equal(t, n, r.Len(), "should contain %d elements", n)
+		assert.True(t, vector.Equal(vector.New(is...), r.Flatten()),
+			"should flatten to original elements")
+	})
+
+	t.Run("Slice", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(42))
+
+		r := vector.NewRope(vector.New(is...))
+		for i := 0; i < 100; i++ {
+			start := rng.Intn(n)
+			end := start + rng.Intn(n-start+1)
+
+			s := r.Slice(start, end)
+			require.Equal(t, end-start, s.Len())
+			if s.Len() > 0 {
+				require.Equal(t, start, s.At(0))
+				require.Equal(t, end-1, s.At(s.Len()-1))
+			}
+
+			// splice the ends back together
+			r2 := r.Slice(0, start).Concat(r.Slice(start, n))
+			require.True(t, vector.Equal(vector.New(is...), r2.Flatten()))
+		}
+
+		assert.Panics(t, func() { r.Slice(-1, 1) }, "should panic when out of bounds")
+		assert.Panics(t, func() { r.Slice(2, 1) }, "should panic when start > end")
+		assert.Panics(t, func() { r.Slice(0, n+1) }, "should panic when out of bounds")
+	})
+
+	t.Run("OutOfBounds", func(t *testing.T) {
+		r := vector.NewRope(vector.New(1, 2, 3))
+		assert.Panics(t, func() { r.At(3) }, "should panic when out of bounds")
+		assert.Panics(t, func() { r.At(-1) }, "should panic when out of bounds")
+	})
+}
@@ -0,0 +1,295 @@
+package vector
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// ErrOutOfRange is returned by the methods of Safe in place of the panics
+// raised by the corresponding methods of Vector.
+var ErrOutOfRange = errors.New("out of range")
+
+// Safe wraps a Vector, replacing its bounds-checking panics with errors
+// wrapping ErrOutOfRange.  Arguments are validated before delegating to
+// the Vector, so panics raised by caller-supplied functions are not
+// intercepted.
+//
+// The zero value is an empty Safe, ready to use.
+type Safe[T any] struct {
+	vec Vector[T]
+}
+
+// NewSafe returns a Safe wrapping v.
+func NewSafe[T any](v Vector[T]) Safe[T] {
+	return Safe[T]{vec: v}
+}
+
+// Unwrap returns the underlying Vector.
+func (s Safe[T]) Unwrap() Vector[T] {
+	return s.vec
+}
+
+// Len returns the number of elements in the underlying Vector.
+func (s Safe[T]) Len() int {
+	return s.vec.cnt
+}
+
+// Append values to the underlying Vector.
+func (s Safe[T]) Append(ts ...T) Safe[T] {
+	return Safe[T]{vec: s.vec.Append(ts...)}
+}
+
+// At returns the ith element.
+func (s Safe[T]) At(i int) (T, error) {
+	if err := s.checkIndex(i, s.vec.cnt); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return s.vec.At(i), nil
+}
+
+// Set assigns t to the index.  As with Vector.Set, an index equal to Len()
+// appends t.
+func (s Safe[T]) Set(index int, t T) (Safe[T], error) {
+	if err := s.checkIndex(index, s.vec.cnt+1); err != nil {
+		return s, err
+	}
+
+	return Safe[T]{vec: s.vec.Set(index, t)}, nil
+}
+
+// Update replaces the element at index with f applied to it.
+func (s Safe[T]) Update(index int, f func(T) T) (Safe[T], error) {
+	if err := s.checkIndex(index, s.vec.cnt); err != nil {
+		return s, err
+	}
+
+	return Safe[T]{vec: s.vec.Update(index, f)}, nil
+}
+
+// ReadAt copies elements, starting at index start, into dst, as in
+// Vector.ReadAt.
+func (s Safe[T]) ReadAt(dst []T, start int) (int, error) {
+	if err := s.checkIndex(start, s.vec.cnt+1); err != nil {
+		return 0, err
+	}
+
+	return s.vec.ReadAt(dst, start), nil
+}
+
+// Slice returns the elements in [start, end).
+func (s Safe[T]) Slice(start, end int) (Safe[T], error) {
+	if err := s.checkRange(start, end); err != nil {
+		return s, err
+	}
+
+	return Safe[T]{vec: s.vec.Slice(start, end)}, nil
+}
+
+// Slice3 is the analog of the full slice expression v[low:high:max].
+func (s Safe[T]) Slice3(low, high, max int) (Safe[T], error) {
+	if high > max || max > s.vec.cnt {
+		return s, fmt.Errorf("%w: slice [%d:%d:%d] with length %d",
+			ErrOutOfRange, low, high, max, s.vec.cnt)
+	}
+
+	return s.Slice(low, high)
+}
+
+// ReverseRange reverses the order of the elements in [start, end).
+func (s Safe[T]) ReverseRange(start, end int) (Safe[T], error) {
+	if err := s.checkRange(start, end); err != nil {
+		return s, err
+	}
+
+	return Safe[T]{vec: s.vec.ReverseRange(start, end)}, nil
+}
+
+// Cut removes the elements in [start, end), returning the remaining
+// elements, followed by the removed ones.
+func (s Safe[T]) Cut(start, end int) (rest, removed Safe[T], err error) {
+	if err = s.checkRange(start, end); err != nil {
+		return s, Safe[T]{}, err
+	}
+
+	r, c := s.vec.Cut(start, end)
+	return Safe[T]{vec: r}, Safe[T]{vec: c}, nil
+}
+
+// Neighborhood returns the elements within radius of index i, clamped to
+// the bounds of the underlying Vector.
+func (s Safe[T]) Neighborhood(i, radius int) (Safe[T], error) {
+	if err := s.checkIndex(i, s.vec.cnt); err != nil {
+		return s, err
+	}
+
+	if radius < 0 {
+		return s, fmt.Errorf("%w: negative radius %d", ErrOutOfRange, radius)
+	}
+
+	return Safe[T]{vec: s.vec.Neighborhood(i, radius)}, nil
+}
+
+// ShiftLeft drops the first n elements and appends n copies of fill, as in
+// Vector.ShiftLeft.
+func (s Safe[T]) ShiftLeft(n int, fill T) (Safe[T], error) {
+	if n < 0 {
+		return s, fmt.Errorf("%w: negative shift amount %d", ErrOutOfRange, n)
+	}
+
+	return Safe[T]{vec: s.vec.ShiftLeft(n, fill)}, nil
+}
+
+// ShiftRight drops the last n elements and prepends n copies of fill, as
+// in Vector.ShiftRight.
+func (s Safe[T]) ShiftRight(n int, fill T) (Safe[T], error) {
+	if n < 0 {
+		return s, fmt.Errorf("%w: negative shift amount %d", ErrOutOfRange, n)
+	}
+
+	return Safe[T]{vec: s.vec.ShiftRight(n, fill)}, nil
+}
+
+// Grow makes room for at least n more elements, as in Vector.Grow.
+func (s Safe[T]) Grow(n int) (Safe[T], error) {
+	if n < 0 {
+		return s, fmt.Errorf("%w: negative count %d", ErrOutOfRange, n)
+	}
+
+	return Safe[T]{vec: s.vec.Grow(n)}, nil
+}
+
+// PushBounded appends item, then drops elements from the front as needed
+// for the length not to exceed max, as in Vector.PushBounded.
+func (s Safe[T]) PushBounded(max int, item T) (Safe[T], error) {
+	if max <= 0 {
+		return s, fmt.Errorf("%w: non-positive bound %d", ErrOutOfRange, max)
+	}
+
+	return Safe[T]{vec: s.vec.PushBounded(max, item)}, nil
+}
+
+// ApplyEdits applies an edit script, as in Vector.ApplyEdits.  The whole
+// script is validated before any edit is applied.
+func (s Safe[T]) ApplyEdits(edits []Edit[T]) (Safe[T], error) {
+	var i, out int // next unread index in s, and length of the result
+	for k, e := range edits {
+		switch e.Op {
+		case OpDelete:
+			if e.Index < i || e.Index >= s.vec.cnt {
+				return s, fmt.Errorf("%w: edit %d: delete index %d with length %d",
+					ErrOutOfRange, k, e.Index, s.vec.cnt)
+			}
+
+			out += e.Index - i
+			i = e.Index + 1
+
+		case OpInsert:
+			n := e.Index - out // elements copied before inserting
+			if n < 0 || i+n > s.vec.cnt {
+				return s, fmt.Errorf("%w: edit %d: insert index %d with length %d",
+					ErrOutOfRange, k, e.Index, s.vec.cnt)
+			}
+
+			out += n + 1
+			i += n
+
+		default:
+			return s, fmt.Errorf("%w: edit %d: invalid op %v", ErrOutOfRange, k, e.Op)
+		}
+	}
+
+	return Safe[T]{vec: s.vec.ApplyEdits(edits)}, nil
+}
+
+// Step returns an iterator over every step-th element, as in Vector.Step.
+// A zero step is reported as an error.
+func (s Safe[T]) Step(start, stop, step int) (iter.Seq2[int, T], error) {
+	switch {
+	case step == 0:
+		return nil, fmt.Errorf("%w: zero step", ErrOutOfRange)
+	case step > 0 && start < stop:
+		if err := s.checkRange(start, stop); err != nil {
+			return nil, err
+		}
+	case step < 0 && start > stop:
+		if stop < -1 || start >= s.vec.cnt {
+			return nil, fmt.Errorf("%w: slice [%d:%d:%d] with length %d",
+				ErrOutOfRange, start, stop, step, s.vec.cnt)
+		}
+	}
+
+	return s.vec.Step(start, stop, step), nil
+}
+
+// SetFrom replaces the element at each of indices with the corresponding
+// element of values, as in Vector.SetFrom.  If indices and values have
+// different lengths, or any index is out of range, no update is applied.
+func (s Safe[T]) SetFrom(indices Vector[int], values Vector[T]) (Safe[T], error) {
+	if indices.cnt != values.cnt {
+		return s, fmt.Errorf("%w: %d indices for %d values",
+			ErrOutOfRange, indices.cnt, values.cnt)
+	}
+
+	var err error
+	indices.forEach(func(_ int, i int) bool {
+		err = s.checkIndex(i, s.vec.cnt)
+		return err == nil
+	})
+	if err != nil {
+		return s, err
+	}
+
+	return Safe[T]{vec: s.vec.SetFrom(indices, values)}, nil
+}
+
+// Reversed returns a read-only view of the underlying Vector in reverse
+// order, as in Vector.Reversed.
+func (s Safe[T]) Reversed() SafeReversedView[T] {
+	return SafeReversedView[T]{r: s.vec.Reversed()}
+}
+
+// SafeReversedView is the analog of ReversedView for Safe.  It is obtained
+// by calling Safe.Reversed.
+type SafeReversedView[T any] struct {
+	r ReversedView[T]
+}
+
+// Len returns the number of elements in the view.
+func (r SafeReversedView[T]) Len() int {
+	return r.r.Len()
+}
+
+// At returns the ith element of the view.
+func (r SafeReversedView[T]) At(i int) (T, error) {
+	if i < 0 || i >= r.r.Len() {
+		var zero T
+		return zero, fmt.Errorf("%w: index %d with length %d", ErrOutOfRange, i, r.r.Len())
+	}
+
+	return r.r.At(i), nil
+}
+
+// Materialize returns the contents of the view as a Safe.
+func (r SafeReversedView[T]) Materialize() Safe[T] {
+	return Safe[T]{vec: r.r.Materialize()}
+}
+
+// checkIndex reports an error unless 0 <= i < limit.
+func (s Safe[T]) checkIndex(i, limit int) error {
+	if i < 0 || i >= limit {
+		return fmt.Errorf("%w: index %d with length %d", ErrOutOfRange, i, s.vec.cnt)
+	}
+
+	return nil
+}
+
+func (s Safe[T]) checkRange(start, end int) error {
+	if start < 0 || end < start || end > s.vec.cnt {
+		return fmt.Errorf("%w: slice [%d:%d] with length %d", ErrOutOfRange, start, end, s.vec.cnt)
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package vector_test
+
+import (
+	"testing"
+
+	"github.com/lthibault/vector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSafe(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	s := vector.NewSafe(vector.Iota(0, n))
+	require.Equal(t, n, s.Len())
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+
+		x, err := s.At(n - 1)
+		require.NoError(t, err)
+		assert.Equal(t, n-1, x)
+
+		s2, err := s.Set(0, -1)
+		require.NoError(t, err)
+		assert.Equal(t, -1, s2.Unwrap().At(0))
+
+		s2, err = s.Set(n, n)
+		require.NoError(t, err, "should append at index Len()")
+		assert.Equal(t, n+1, s2.Len())
+
+		s2, err = s.Update(1, func(i int) int { return i * 10 })
+		require.NoError(t, err)
+		assert.Equal(t, 10, s2.Unwrap().At(1))
+
+		s2, err = s.Slice(10, 20)
+		require.NoError(t, err)
+		assert.Equal(t, 10, s2.Len())
+
+		s2, err = s.Slice3(10, 20, 30)
+		require.NoError(t, err)
+		assert.Equal(t, 10, s2.Len())
+
+		s2, err = s.ReverseRange(0, 3)
+		require.NoError(t, err)
+		assert.Equal(t, 2, s2.Unwrap().At(0))
+
+		rest, removed, err := s.Cut(0, 100)
+		require.NoError(t, err)
+		assert.Equal(t, n-100, rest.Len())
+		assert.Equal(t, 100, removed.Len())
+
+		s2, err = s.Neighborhood(0, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 3, s2.Len())
+
+		k, err := s.ReadAt(make([]int, 10), n)
+		require.NoError(t, err)
+		assert.Zero(t, k)
+
+		seq, err := s.Step(n-1, -1, -1000)
+		require.NoError(t, err)
+		var is []int
+		for i := range seq {
+			is = append(is, i)
+		}
+		assert.Equal(t, []int{n - 1, n - 1001, n - 2001, n - 3001, n - 4001}, is)
+
+		s2, err = s.SetFrom(vector.New(0, n-1), vector.New(-1, -2))
+		require.NoError(t, err)
+		assert.Equal(t, -2, s2.Unwrap().At(n-1))
+
+		s2, err = s.ShiftLeft(1, -1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, s2.Unwrap().At(0))
+		assert.Equal(t, -1, s2.Unwrap().At(n-1))
+
+		s2, err = s.ShiftRight(1, -1)
+		require.NoError(t, err)
+		assert.Equal(t, -1, s2.Unwrap().At(0))
+		assert.Equal(t, n-2, s2.Unwrap().At(n-1))
+
+		s2, err = s.Grow(n)
+		require.NoError(t, err)
+		assert.Equal(t, n, s2.Len())
+
+		s2, err = s.PushBounded(n, n)
+		require.NoError(t, err)
+		assert.Equal(t, n, s2.Len())
+		assert.Equal(t, 1, s2.Unwrap().At(0))
+
+		s2, err = s.ApplyEdits([]vector.Edit[int]{
+			{Op: vector.OpDelete, Index: 0},
+			{Op: vector.OpInsert, Index: n - 1, Value: -1},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, n, s2.Len())
+		assert.Equal(t, 1, s2.Unwrap().At(0))
+		assert.Equal(t, -1, s2.Unwrap().At(n-1))
+
+		r := s.Reversed()
+		assert.Equal(t, n, r.Len())
+		x, err = r.At(0)
+		require.NoError(t, err)
+		assert.Equal(t, n-1, x)
+		assert.Equal(t, 0, r.Materialize().Unwrap().At(n-1))
+	})
+
+	t.Run("OutOfRange", func(t *testing.T) {
+		t.Parallel()
+
+		for name, f := range map[string]func() error{
+			"At":           func() error { _, err := s.At(n); return err },
+			"AtNegative":   func() error { _, err := s.At(-1); return err },
+			"Set":          func() error { _, err := s.Set(n+1, 0); return err },
+			"Update":       func() error { _, err := s.Update(n, func(i int) int { return i }); return err },
+			"ReadAt":       func() error { _, err := s.ReadAt(nil, n+1); return err },
+			"Slice":        func() error { _, err := s.Slice(5, 4); return err },
+			"Slice3":       func() error { _, err := s.Slice3(0, 10, n+1); return err },
+			"ReverseRange": func() error { _, err := s.ReverseRange(0, n+1); return err },
+			"Cut":          func() error { _, _, err := s.Cut(-1, 0); return err },
+			"Neighborhood": func() error { _, err := s.Neighborhood(0, -1); return err },
+			"Step":         func() error { _, err := s.Step(0, n+1, 1); return err },
+			"StepReverse":  func() error { _, err := s.Step(n, 0, -1); return err },
+			"StepZero":     func() error { _, err := s.Step(0, 1, 0); return err },
+			"SetFrom":      func() error { _, err := s.SetFrom(vector.New(0, n), vector.New(1, 2)); return err },
+			"SetFromLen":   func() error { _, err := s.SetFrom(vector.New(0), vector.New(1, 2)); return err },
+			"Reversed":     func() error { _, err := s.Reversed().At(n); return err },
+			"ReversedNeg":  func() error { _, err := s.Reversed().At(-1); return err },
+			"ShiftLeft":    func() error { _, err := s.ShiftLeft(-1, 0); return err },
+			"ShiftRight":   func() error { _, err := s.ShiftRight(-1, 0); return err },
+			"Grow":         func() error { _, err := s.Grow(-1); return err },
+			"PushBounded":  func() error { _, err := s.PushBounded(0, 0); return err },
+			"ApplyEdits": func() error {
+				_, err := s.ApplyEdits([]vector.Edit[int]{{Op: vector.OpDelete, Index: n}})
+				return err
+			},
+		} {
+			assert.ErrorIs(t, f(), vector.ErrOutOfRange, name)
+		}
+	})
+
+	t.Run("ZeroValue", func(t *testing.T) {
+		t.Parallel()
+
+		var z vector.Safe[int]
+		_, err := z.At(0)
+		assert.ErrorIs(t, err, vector.ErrOutOfRange)
+
+		z = z.Append(1)
+		x, err := z.At(0)
+		require.NoError(t, err)
+		assert.Equal(t, 1, x)
+	})
+}
+
+func TestSafeApplyEdits(t *testing.T) {
+	t.Parallel()
+
+	s := vector.NewSafe(vector.New(0, 1, 2, 3))
+
+	for name, edits := range map[string][]vector.Edit[int]{
+		"DeleteUnordered": {{Op: vector.OpDelete, Index: 2}, {Op: vector.OpDelete, Index: 1}},
+		"DeleteNegative":  {{Op: vector.OpDelete, Index: -1}},
+		"DeletePastEnd":   {{Op: vector.OpDelete, Index: 4}},
+		"InsertUnordered": {{Op: vector.OpInsert, Index: 2}, {Op: vector.OpInsert, Index: 1}},
+		"InsertPastEnd":   {{Op: vector.OpInsert, Index: 6}},
+		"InvalidOp":       {{Op: 0}},
+	} {
+		_, err := s.ApplyEdits(edits)
+		assert.ErrorIs(t, err, vector.ErrOutOfRange, name)
+		assert.NotPanics(t, func() { s.ApplyEdits(edits) }, name)
+	}
+
+	// Valid scripts agree with Vector.ApplyEdits.
+	edits := vector.Diff(s.Unwrap(), vector.New(1, -1, 3, 4))
+	got, err := s.ApplyEdits(edits)
+	require.NoError(t, err)
+	assert.True(t, vector.Equal(vector.New(1, -1, 3, 4), got.Unwrap()))
+}
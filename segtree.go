@@ -0,0 +1,75 @@
+package vector
+
+// SegmentTree answers range-aggregate queries over the elements of a
+// Vector in O(log n) time.  It is built once, in O(n) time, and is not
+// affected by subsequent changes to the vector from which it was built.
+//
+// A SegmentTree is safe for concurrent use by multiple readers.
+type SegmentTree[T any] struct {
+	n       int
+	tree    []T // tree[n:] holds the leaves; tree[i] aggregates its children
+	combine func(T, T) T
+}
+
+// BuildSegmentTree returns a SegmentTree over the elements of v.  The
+// combine function MUST be associative, but need not be commutative.
+func BuildSegmentTree[T any](v Vector[T], combine func(T, T) T) *SegmentTree[T] {
+	tree := make([]T, 2*v.cnt)
+	v.ReadAt(tree[v.cnt:], 0)
+	for i := v.cnt - 1; i > 0; i-- {
+		tree[i] = combine(tree[2*i], tree[2*i+1])
+	}
+
+	return &SegmentTree[T]{n: v.cnt, tree: tree, combine: combine}
+}
+
+// Len returns the number of elements covered by the tree.
+func (s *SegmentTree[T]) Len() int {
+	return s.n
+}
+
+// Query returns the aggregate of the elements in the half-open range
+// [lo, hi), combined in index order.  It panics if the range is empty or
+// out of bounds.
+func (s *SegmentTree[T]) Query(lo, hi int) T {
+	if lo < 0 || hi > s.n || lo > hi {
+		panic("slice bounds out of range")
+	}
+
+	if lo == hi {
+		panic("empty range")
+	}
+
+	// Left and right partial aggregates are kept separate so that
+	// non-commutative operations are combined in order.
+	var left, right T
+	var hasLeft, hasRight bool
+	for lo, hi = lo+s.n, hi+s.n; lo < hi; lo, hi = lo/2, hi/2 {
+		if lo&1 == 1 {
+			if hasLeft {
+				left = s.combine(left, s.tree[lo])
+			} else {
+				left, hasLeft = s.tree[lo], true
+			}
+			lo++
+		}
+
+		if hi&1 == 1 {
+			hi--
+			if hasRight {
+				right = s.combine(s.tree[hi], right)
+			} else {
+				right, hasRight = s.tree[hi], true
+			}
+		}
+	}
+
+	switch {
+	case !hasRight:
+		return left
+	case !hasLeft:
+		return right
+	default:
+		return s.combine(left, right)
+	}
+}
@@ -0,0 +1,61 @@
+package vector_test
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/lthibault/vector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSegmentTree(t *testing.T) {
+	t.Parallel()
+
+	const n = 1000
+
+	rng := rand.New(rand.NewSource(42))
+	v := vector.Unfold(0, func(i int) (int, int, bool) {
+		return rng.Intn(n), i + 1, i < n
+	})
+
+	sum := vector.BuildSegmentTree(v, func(a, b int) int { return a + b })
+	minimum := vector.BuildSegmentTree(v, func(a, b int) int { return min(a, b) })
+	require.Equal(t, n, sum.Len())
+
+	for q := 0; q < 1000; q++ {
+		lo := rng.Intn(n)
+		hi := lo + 1 + rng.Intn(n-lo)
+
+		wantSum, wantMin := 0, v.At(lo)
+		for i := lo; i < hi; i++ {
+			wantSum += v.At(i)
+			wantMin = min(wantMin, v.At(i))
+		}
+
+		require.Equal(t, wantSum, sum.Query(lo, hi), "sum of [%d, %d)", lo, hi)
+		require.Equal(t, wantMin, minimum.Query(lo, hi), "min of [%d, %d)", lo, hi)
+	}
+
+	t.Run("NonCommutative", func(t *testing.T) {
+		t.Parallel()
+
+		digits := vector.Unfold(0, func(i int) (string, int, bool) {
+			return strconv.Itoa(i % 10), i + 1, i < 37
+		})
+		concat := vector.BuildSegmentTree(digits, func(a, b string) string { return a + b })
+
+		assert.Equal(t, "0123456789012345678901234567890123456", concat.Query(0, 37))
+		assert.Equal(t, "789012", concat.Query(7, 13))
+		assert.Equal(t, "5", concat.Query(5, 6))
+	})
+
+	t.Run("Bounds", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Panics(t, func() { sum.Query(-1, 1) })
+		assert.Panics(t, func() { sum.Query(0, n+1) })
+		assert.Panics(t, func() { sum.Query(3, 3) })
+	})
+}
@@ -0,0 +1,348 @@
+package vector
+
+import (
+	"cmp"
+	"slices"
+)
+
+// IsSorted reports whether the elements of v are in ascending order.
+// Empty and single-element vectors are sorted.
+func IsSorted[T cmp.Ordered](v Vector[T]) bool {
+	return IsSortedFunc(v, cmp.Compare[T])
+}
+
+// IsSortedFunc is like IsSorted, but orders elements using cmp.  It stops
+// at the first pair of elements that is out of order.
+func IsSortedFunc[T any](v Vector[T], cmp func(a, b T) int) bool {
+	var prev T
+	return v.forEach(func(i int, t T) bool {
+		if i > 0 && cmp(prev, t) > 0 {
+			return false
+		}
+
+		prev = t
+		return true
+	})
+}
+
+// CountLess returns the number of elements of v that are less than target,
+// which is the index at which target would be inserted to keep v sorted.
+// It uses binary search, so v MUST be sorted in ascending order.
+func CountLess[T cmp.Ordered](v Vector[T], target T) int {
+	return lowerBound(v, func(t T) bool { return cmp.Less(t, target) })
+}
+
+// CountLessEqual is like CountLess, but also counts the elements equal to
+// target.
+func CountLessEqual[T cmp.Ordered](v Vector[T], target T) int {
+	return lowerBound(v, func(t T) bool { return !cmp.Less(target, t) })
+}
+
+// lowerBound returns the number of leading elements of v that satisfy
+// pred, assuming that no element satisfying pred follows one that does not.
+func lowerBound[T any](v Vector[T], pred func(T) bool) int {
+	lo, hi := 0, v.cnt
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if pred(v.At(mid)) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+
+	return lo
+}
+
+// KSmallest returns the k smallest elements of v in ascending order.  It
+// runs in O(n log k) time using a bounded heap.  If k >= v.Len(), the
+// result is a sorted copy of v.
+func KSmallest[T cmp.Ordered](v Vector[T], k int) Vector[T] {
+	return KSmallestFunc(v, k, cmp.Compare[T])
+}
+
+// KLargest returns the k largest elements of v in descending order.
+func KLargest[T cmp.Ordered](v Vector[T], k int) Vector[T] {
+	return KSmallestFunc(v, k, func(a, b T) int { return cmp.Compare(b, a) })
+}
+
+// KSmallestFunc is like KSmallest, but orders elements using cmp, which
+// MUST return a negative number when a < b, a positive number when a > b,
+// and zero otherwise.
+func KSmallestFunc[T any](v Vector[T], k int, cmp func(a, b T) int) Vector[T] {
+	if k <= 0 {
+		return Vector[T]{}
+	}
+
+	// h is a max-heap containing the k smallest elements seen so far
+	h := make([]T, 0, min(k, v.cnt))
+	v.forEach(func(_ int, t T) bool {
+		if len(h) < k {
+			h = append(h, t)
+			siftUp(h, len(h)-1, cmp)
+		} else if cmp(t, h[0]) < 0 {
+			h[0] = t
+			siftDown(h, 0, cmp)
+		}
+
+		return true
+	})
+
+	slices.SortFunc(h, cmp)
+	return New(h...)
+}
+
+// NthElement returns the element that would be at index n if v were
+// sorted, and true.  It runs in O(n) average time using quickselect, on a
+// scratch copy of v.  If n is out of bounds, it returns the zero value and
+// false.
+func NthElement[T cmp.Ordered](v Vector[T], n int) (T, bool) {
+	return NthElementFunc(v, n, cmp.Compare[T])
+}
+
+// NthElementFunc is like NthElement, but orders elements using cmp.
+func NthElementFunc[T any](v Vector[T], n int, cmp func(a, b T) int) (T, bool) {
+	if n < 0 || n >= v.cnt {
+		var zero T
+		return zero, false
+	}
+
+	s := v.toSlice()
+	for lo, hi := 0, len(s); hi-lo > 1; {
+		// Three-way partition of s[lo:hi] into elements less than, equal
+		// to, and greater than the pivot, so that runs of duplicates do not
+		// degrade to quadratic time.
+		p := medianOfThree(s[lo], s[lo+(hi-lo)/2], s[hi-1], cmp)
+		lt, i, gt := lo, lo, hi
+		for i < gt {
+			switch c := cmp(s[i], p); {
+			case c < 0:
+				s[lt], s[i] = s[i], s[lt]
+				lt++
+				i++
+			case c > 0:
+				gt--
+				s[i], s[gt] = s[gt], s[i]
+			default:
+				i++
+			}
+		}
+
+		switch {
+		case n < lt:
+			hi = lt
+		case n >= gt:
+			lo = gt
+		default:
+			return s[n], true
+		}
+	}
+
+	return s[n], true
+}
+
+func medianOfThree[T any](a, b, c T, cmp func(a, b T) int) T {
+	if cmp(a, b) > 0 {
+		a, b = b, a
+	}
+
+	if cmp(b, c) > 0 {
+		b = c
+		if cmp(a, b) > 0 {
+			b = a
+		}
+	}
+
+	return b
+}
+
+// MergeK merges vectors that are each sorted in ascending order into a
+// single sorted vector, in O(n log k) time for k vectors holding n elements
+// in total.  Equal elements retain the order of their arguments.  If any
+// vector is not sorted, the order of the result is unspecified.
+func MergeK[T cmp.Ordered](vs ...Vector[T]) Vector[T] {
+	return MergeKFunc(cmp.Compare[T], vs...)
+}
+
+// MergeKFunc is like MergeK, but orders elements using cmp.
+func MergeKFunc[T any](cmp func(a, b T) int, vs ...Vector[T]) Vector[T] {
+	// h is a heap of cursors into the non-empty vectors, arranged such that
+	// the cursor with the smallest head is at the root.
+	type cursor struct {
+		k, i int // vector, and index of head within it
+		leaf *node[T]
+		head T
+	}
+
+	h := make([]cursor, 0, len(vs))
+	before := func(a, b cursor) int {
+		if c := cmp(b.head, a.head); c != 0 {
+			return c
+		}
+
+		return b.k - a.k
+	}
+
+	for k, v := range vs {
+		if v.cnt > 0 {
+			c := cursor{k: k, leaf: v.nodeFor(0)}
+			c.head, _ = c.leaf.array[0].(T)
+			h = append(h, c)
+			siftUp(h, len(h)-1, before)
+		}
+	}
+
+	b := NewBuilder[T]()
+	for len(h) > 0 {
+		c := &h[0]
+		b.Cons(c.head)
+
+		if c.i++; c.i < vs[c.k].cnt {
+			if c.i&mask == 0 {
+				c.leaf = vs[c.k].nodeFor(c.i)
+			}
+			c.head, _ = c.leaf.array[c.i&mask].(T)
+		} else {
+			h[0] = h[len(h)-1]
+			h = h[:len(h)-1]
+		}
+
+		siftDown(h, 0, before)
+	}
+
+	return b.Vector()
+}
+
+// TopK returns the k most frequent elements of v, paired with their
+// number of occurrences, in descending order of frequency.  Elements that
+// occur equally often are ordered by their first occurrence in v.  If k
+// exceeds the number of distinct elements, all of them are returned.
+func TopK[T comparable](v Vector[T], k int) Vector[Pair[T, int]] {
+	if k <= 0 {
+		return Vector[Pair[T, int]]{}
+	}
+
+	type entry struct {
+		t            T
+		count, first int
+	}
+
+	var entries []*entry
+	m := make(map[T]*entry)
+	v.forEach(func(i int, t T) bool {
+		e, ok := m[t]
+		if !ok {
+			e = &entry{t: t, first: i}
+			m[t] = e
+			entries = append(entries, e)
+		}
+
+		e.count++
+		return true
+	})
+
+	// rank orders entries from most to least frequent
+	rank := func(a, b *entry) int {
+		if a.count != b.count {
+			return b.count - a.count
+		}
+
+		return a.first - b.first
+	}
+
+	// h is a max-heap by rank, holding the k best entries seen so far
+	h := make([]*entry, 0, min(k, len(entries)))
+	for _, e := range entries {
+		if len(h) < k {
+			h = append(h, e)
+			siftUp(h, len(h)-1, rank)
+		} else if rank(e, h[0]) < 0 {
+			h[0] = e
+			siftDown(h, 0, rank)
+		}
+	}
+
+	slices.SortFunc(h, rank)
+
+	b := NewBuilder[Pair[T, int]]()
+	for _, e := range h {
+		b.Cons(Pair[T, int]{First: e.t, Second: e.count})
+	}
+
+	return b.Vector()
+}
+
+// siftUp restores the max-heap property of h after h[i] has been added.
+func siftUp[T any](h []T, i int, cmp func(a, b T) int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if cmp(h[i], h[parent]) <= 0 {
+			return
+		}
+
+		h[i], h[parent] = h[parent], h[i]
+		i = parent
+	}
+}
+
+// siftDown restores the max-heap property of h after h[i] has been
+// replaced.
+func siftDown[T any](h []T, i int, cmp func(a, b T) int) {
+	for {
+		largest, left, right := i, 2*i+1, 2*i+2
+		if left < len(h) && cmp(h[left], h[largest]) > 0 {
+			largest = left
+		}
+		if right < len(h) && cmp(h[right], h[largest]) > 0 {
+			largest = right
+		}
+
+		if largest == i {
+			return
+		}
+
+		h[i], h[largest] = h[largest], h[i]
+		i = largest
+	}
+}
+
+// SortAdapter exposes the contents of a Vector through sort.Interface, so
+// that they can be sorted in place with sort.Sort or sort.Stable.  Swaps are
+// applied to a private Builder, and Result returns the sorted Vector.
+//
+// A SortAdapter is single-use:  it MUST NOT be used after calling Result.
+// It is not safe for concurrent use.
+type SortAdapter[T any] struct {
+	b    *Builder[T]
+	less func(a, b T) bool
+}
+
+// AsSortInterface returns a SortAdapter that orders the elements of v
+// using less.  The receiver is not modified.
+func (v Vector[T]) AsSortInterface(less func(a, b T) bool) *SortAdapter[T] {
+	return &SortAdapter[T]{b: v.transient(), less: less}
+}
+
+// Len is the number of elements being sorted.
+func (s *SortAdapter[T]) Len() int { return s.b.cnt }
+
+// Less reports whether the element at index i sorts before that at j.
+func (s *SortAdapter[T]) Less(i, j int) bool {
+	v := s.b.Vector()
+	return s.less(v.At(i), v.At(j))
+}
+
+// Swap exchanges the elements at indices i and j.
+func (s *SortAdapter[T]) Swap(i, j int) {
+	if i < 0 || j < 0 || i >= s.b.cnt || j >= s.b.cnt {
+		panic("index out of bounds")
+	}
+
+	a, b := s.b.editableNodeFor(i), s.b.editableNodeFor(j)
+	a.array[i&mask], b.array[j&mask] = b.array[j&mask], a.array[i&mask]
+}
+
+// Result returns the current contents of the adapter as a Vector.
+func (s *SortAdapter[T]) Result() Vector[T] {
+	return s.b.Vector()
+}
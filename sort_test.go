@@ -0,0 +1,299 @@
+package vector_test
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/lthibault/vector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKSmallest(t *testing.T) {
+	t.Parallel()
+
+	const n, k = 4096, 50
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = (i * 7919) % n // permutation of [0, n)
+	}
+
+	v := vector.New(is...)
+
+	t.Run("Smallest", func(t *testing.T) {
+		s := vector.KSmallest(v, k)
+		require.Equal(t, k, s.Len(), "should return %d elements", k)
+		for i := 0; i < k; i++ {
+			assert.Equal(t, i, s.At(i))
+		}
+	})
+
+	t.Run("Largest", func(t *testing.T) {
+		s := vector.KLargest(v, k)
+		require.Equal(t, k, s.Len(), "should return %d elements", k)
+		for i := 0; i < k; i++ {
+			assert.Equal(t, n-1-i, s.At(i))
+		}
+	})
+
+	t.Run("Func", func(t *testing.T) {
+		type item struct{ key int }
+
+		b := vector.NewBuilder[item]()
+		for _, i := range is {
+			b.Append(item{key: i})
+		}
+
+		s := vector.KSmallestFunc(b.Vector(), 3, func(a, b item) int {
+			return a.key - b.key
+		})
+		require.Equal(t, 3, s.Len())
+		assert.Equal(t, item{key: 2}, s.At(2))
+	})
+
+	t.Run("Edges", func(t *testing.T) {
+		all := vector.KSmallest(v, n+1)
+		require.Equal(t, n, all.Len(), "should return sorted copy")
+		sorted := slices.Sorted(slices.Values(is))
+		for i := 0; i < n; i++ {
+			require.Equal(t, sorted[i], all.At(i))
+		}
+
+		assert.Zero(t, vector.KSmallest(v, 0).Len(), "k=0 should be empty")
+		assert.Zero(t, vector.KSmallest(vector.Vector[int]{}, k).Len(),
+			"empty input should yield empty result")
+	})
+}
+
+func TestAsSortInterface(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	rng := rand.New(rand.NewSource(42))
+	is := rng.Perm(n)
+	v := vector.New(is...)
+
+	s := v.AsSortInterface(func(a, b int) bool { return a < b })
+	sort.Sort(s)
+
+	sorted := s.Result()
+	require.Equal(t, n, sorted.Len())
+	for i := 0; i < n; i++ {
+		require.Equal(t, i, sorted.At(i), "should be sorted")
+		require.Equal(t, is[i], v.At(i), "should not mutate receiver")
+	}
+
+	type pair struct{ key, seq int }
+
+	b := vector.NewBuilder[pair]()
+	for i := 0; i < n; i++ {
+		b.Append(pair{key: i % 3, seq: i})
+	}
+
+	st := b.Vector().AsSortInterface(func(a, b pair) bool { return a.key < b.key })
+	sort.Stable(st)
+
+	res := st.Result()
+	for i := 1; i < n; i++ {
+		prev, cur := res.At(i-1), res.At(i)
+		if prev.key == cur.key {
+			require.Less(t, prev.seq, cur.seq, "should support stable sorting")
+		}
+	}
+}
+
+func TestIsSorted(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	v := vector.Iota(0, n)
+	assert.True(t, vector.IsSorted(v))
+	assert.True(t, vector.IsSorted(vector.Vector[int]{}), "empty vector should be sorted")
+	assert.True(t, vector.IsSorted(vector.New(42)), "singleton should be sorted")
+	assert.True(t, vector.IsSorted(vector.New(1, 1, 2, 2)), "duplicates should be allowed")
+
+	// disorder in the trie, and in the tail
+	assert.False(t, vector.IsSorted(v.Set(n/2, -1)))
+	assert.False(t, vector.IsSorted(v.Set(n-1, 0)))
+
+	desc := func(a, b int) int { return b - a }
+	assert.True(t, vector.IsSortedFunc(v.Reverse(), desc))
+	assert.False(t, vector.IsSortedFunc(v, desc))
+}
+
+func TestNthElement(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	rng := rand.New(rand.NewSource(42))
+	is := make([]int, n)
+	for i := range is {
+		is[i] = rng.Intn(n / 4) // plenty of duplicates
+	}
+	v := vector.New(is...)
+
+	sorted := slices.Clone(is)
+	slices.Sort(sorted)
+
+	for _, k := range []int{0, 1, n / 2, n - 2, n - 1} {
+		got, ok := vector.NthElement(v, k)
+		require.True(t, ok)
+		assert.Equal(t, sorted[k], got, "index %d", k)
+	}
+
+	for k := 0; k < n; k += 97 {
+		got, ok := vector.NthElementFunc(v, k, func(a, b int) int { return b - a })
+		require.True(t, ok)
+		assert.Equal(t, sorted[n-1-k], got, "descending index %d", k)
+	}
+
+	for i, x := range is {
+		require.Equal(t, x, v.At(i), "should not modify the original")
+	}
+
+	_, ok := vector.NthElement(v, -1)
+	assert.False(t, ok)
+	_, ok = vector.NthElement(v, n)
+	assert.False(t, ok)
+	_, ok = vector.NthElement(vector.Vector[int]{}, 0)
+	assert.False(t, ok)
+}
+
+func TestMergeK(t *testing.T) {
+	t.Parallel()
+
+	const k, n = 7, 4096
+
+	rng := rand.New(rand.NewSource(42))
+	var all []int
+	vs := make([]vector.Vector[int], k)
+	for j := range vs {
+		is := make([]int, rng.Intn(n))
+		for i := range is {
+			is[i] = rng.Intn(n)
+		}
+		slices.Sort(is)
+
+		vs[j] = vector.New(is...)
+		all = append(all, is...)
+	}
+	slices.Sort(all)
+
+	got := vector.MergeK(vs...)
+	require.Equal(t, len(all), got.Len())
+	for i, x := range all {
+		require.Equal(t, x, got.At(i), "index %d", i)
+	}
+
+	t.Run("Stable", func(t *testing.T) {
+		t.Parallel()
+
+		type item struct{ key, src int }
+		byKey := func(a, b item) int { return a.key - b.key }
+
+		a := vector.New(item{1, 0}, item{2, 0}, item{2, 0})
+		b := vector.New(item{0, 1}, item{2, 1}, item{3, 1})
+		got := vector.MergeKFunc(byKey, a, b)
+
+		assert.True(t, vector.Equal(vector.New(
+			item{0, 1}, item{1, 0}, item{2, 0}, item{2, 0}, item{2, 1}, item{3, 1},
+		), got))
+	})
+
+	assert.Zero(t, vector.MergeK[int]().Len())
+	assert.Zero(t, vector.MergeK(vector.Vector[int]{}, vector.New[int]()).Len())
+}
+
+func TestCountLess(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	rng := rand.New(rand.NewSource(42))
+	is := make([]int, n)
+	for i := range is {
+		is[i] = rng.Intn(n / 4) // plenty of duplicates
+	}
+	slices.Sort(is)
+	v := vector.New(is...)
+
+	for target := -1; target <= n/4; target++ {
+		less, lessEq := 0, 0
+		for _, x := range is {
+			if x < target {
+				less++
+			}
+			if x <= target {
+				lessEq++
+			}
+		}
+
+		require.Equal(t, less, vector.CountLess(v, target), "target %d", target)
+		require.Equal(t, lessEq, vector.CountLessEqual(v, target), "target %d", target)
+	}
+
+	assert.Zero(t, vector.CountLess(vector.Vector[int]{}, 0))
+	assert.Zero(t, vector.CountLessEqual(vector.Vector[int]{}, 0))
+}
+
+func TestTopK(t *testing.T) {
+	t.Parallel()
+
+	// "c" x4, "a" x3, "b" x3, "d" x1, with "b" occurring before "a"
+	v := vector.New(strings.Split("b c a c d a b c b a c", " ")...)
+
+	got := vector.TopK(v, 3)
+	assert.True(t, vector.Equal(vector.New(
+		vector.Pair[string, int]{First: "c", Second: 4},
+		vector.Pair[string, int]{First: "b", Second: 3},
+		vector.Pair[string, int]{First: "a", Second: 3},
+	), got), "should break ties by first occurrence")
+
+	all := vector.TopK(v, 10)
+	require.Equal(t, 4, all.Len(), "should return every distinct element")
+	assert.Equal(t, vector.Pair[string, int]{First: "d", Second: 1}, all.At(3))
+
+	assert.Zero(t, vector.TopK(v, 0).Len())
+	assert.Zero(t, vector.TopK(vector.Vector[string]{}, 3).Len())
+
+	t.Run("Large", func(t *testing.T) {
+		t.Parallel()
+
+		const n = 4096
+
+		rng := rand.New(rand.NewSource(42))
+		is := make([]int, n)
+		for i := range is {
+			is[i] = rng.Intn(200)
+		}
+		v := vector.New(is...)
+
+		hist := vector.Histogram(v)
+		got := vector.TopK(v, 20)
+		require.Equal(t, 20, got.Len())
+		for i := 0; i < got.Len(); i++ {
+			p := got.At(i)
+			require.Equal(t, hist[p.First], p.Second, "should report counts")
+			if i > 0 {
+				require.LessOrEqual(t, p.Second, got.At(i-1).Second, "should be ordered")
+			}
+		}
+
+		// nothing left out may be more frequent than the last one kept
+		kept := vector.ToSet(vector.MapStateful(got, 0, func(s int, p vector.Pair[int, int]) (int, int) {
+			return s, p.First
+		}))
+		for x, c := range hist {
+			if _, ok := kept[x]; !ok {
+				require.LessOrEqual(t, c, got.At(19).Second)
+			}
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package vector
+
+// Stack is an immutable LIFO stack backed by a Vector.  Push and Pop
+// operate on the Vector's tail, and each returns a new Stack, leaving the
+// receiver unchanged.
+//
+// The zero value is an empty Stack, ready to use.
+type Stack[T any] struct {
+	vec Vector[T]
+}
+
+// Len returns the number of elements on the Stack.
+func (s Stack[T]) Len() int {
+	return s.vec.Len()
+}
+
+// Push returns a new Stack with t on top.
+func (s Stack[T]) Push(t T) Stack[T] {
+	return Stack[T]{vec: s.vec.cons(t)}
+}
+
+// Pop returns the top element and a new Stack without it.  If the Stack is
+// empty, ok is false and the receiver is returned unchanged.
+func (s Stack[T]) Pop() (t T, rest Stack[T], ok bool) {
+	if t, ok = s.Peek(); !ok {
+		return t, s, false
+	}
+
+	return t, Stack[T]{vec: s.vec.Pop()}, true
+}
+
+// Peek returns the top element of the Stack.  If the Stack is empty, ok is
+// false.
+func (s Stack[T]) Peek() (t T, ok bool) {
+	if s.vec.cnt == 0 {
+		return t, false
+	}
+
+	return s.vec.At(s.vec.cnt - 1), true
+}
+
+// Vector returns the elements of the Stack, from bottom to top.
+func (s Stack[T]) Vector() Vector[T] {
+	return s.vec
+}
@@ -0,0 +1,44 @@
+package vector_test
+
+import (
+	"testing"
+
+	"github.com/lthibault/vector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStack(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	var s vector.Stack[int]
+	_, ok := s.Peek()
+	assert.False(t, ok, "empty stack should have no top")
+
+	_, rest, ok := s.Pop()
+	assert.False(t, ok, "popping empty stack should fail")
+	assert.Zero(t, rest.Len())
+
+	for i := 0; i < n; i++ {
+		s = s.Push(i)
+	}
+	require.Equal(t, n, s.Len(), "should contain %d elements", n)
+
+	top, ok := s.Peek()
+	require.True(t, ok)
+	assert.Equal(t, n-1, top)
+
+	snapshot := s
+	for i := n - 1; i >= 0; i-- {
+		var x int
+		x, s, ok = s.Pop()
+		require.True(t, ok)
+		require.Equal(t, i, x, "should pop in LIFO order")
+	}
+
+	assert.Zero(t, s.Len(), "should be empty")
+	assert.Equal(t, n, snapshot.Len(), "should not mutate earlier versions")
+	assert.Equal(t, n-1, snapshot.Vector().At(n-1))
+}
@@ -1,11 +1,23 @@
 package vector
 
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+	"strings"
+)
+
 const (
 	bits  = 5 // number of bits needed to represent the range (0 32].
 	width = 32
 	mask  = width - 1 // 0x1f
 )
 
+// ErrCapacityExceeded is returned when an operation would grow a Vector
+// beyond a caller-supplied bound.
+var ErrCapacityExceeded = errors.New("capacity exceeded")
+
 // Vector is an immutable vector implementation with O(1) lookup,
 // insertion, appending, and deletion.
 type Vector[T any] struct {
@@ -23,12 +35,16 @@ func New[T any](items ...T) (vec Vector[T]) {
 	return
 }
 
+// Empty returns the zero-value Vector.
+func Empty[T any]() Vector[T] {
+	return Vector[T]{}
+}
+
 func newVector[T any]() Vector[T] {
-	node := &node[T]{}
 	return Vector[T]{
 		shift: bits,
-		root:  node,
-		tail:  node,
+		root:  &node[T]{},
+		tail:  &node[T]{},
 	}
 }
 
@@ -38,23 +54,39 @@ func newVector[T any]() Vector[T] {
 // When the transient vector has reached the desired state, it should be
 // persisted with a call to Persistent() prior to sharing.
 func (v Vector[T]) transient() *Builder[T] {
-	if v == (Vector[T]{}) {
+	if v.IsZero() {
 		v = newVector[T]()
 	}
 
+	edit := new(owner)
 	return &Builder[T]{
 		cnt:   v.cnt,
 		shift: v.shift,
-		root:  v.root.clone(),
-		tail:  v.tail.clone(),
+		root:  v.root.editable(edit),
+		tail:  v.tail.editable(edit),
+		edit:  edit,
 	}
 }
 
+// IsZero reports whether v is the zero-value Vector.  Vectors obtained from
+// a Builder are initialized even when they are empty, so an empty Vector
+// is not necessarily zero.  Use Len to test for emptiness.
+func (v Vector[T]) IsZero() bool {
+	return v == Vector[T]{}
+}
+
 // Len returns the number of elements contained in the Vector.
 func (v Vector[T]) Len() int {
 	return v.cnt
 }
 
+// TailSpace returns the number of elements that can be appended to the
+// Vector before its tail fills up, and the next append must push it into
+// the trie.
+func (v Vector[T]) TailSpace() int {
+	return width - (v.cnt - v.tailoff())
+}
+
 func (v Vector[T]) tailoff() int {
 	if v.cnt < width {
 		return 0
@@ -80,12 +112,216 @@ func (v Vector[T]) nodeFor(i int) *node[T] {
 	panic("index out of bounds")
 }
 
+// Equals reports whether v and other have the same length and deeply equal
+// elements, as defined by reflect.DeepEqual.  Leaves shared by both vectors
+// are not compared.  For comparable element types, the Equal function is
+// faster.
+func (v Vector[T]) Equals(other Vector[T]) bool {
+	if v.cnt != other.cnt {
+		return false
+	}
+
+	if v.root == other.root && v.tail == other.tail {
+		return true
+	}
+
+	for i := 0; i < v.cnt; i += width {
+		a, b := v.nodeFor(i), other.nodeFor(i)
+		if a == b {
+			continue
+		}
+
+		for j := 0; j < width && i+j < v.cnt; j++ {
+			if !reflect.DeepEqual(a.array[j], b.array[j]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
 // At i returns the ith entry in the Vector
 func (v Vector[T]) At(i int) T {
 	t, _ := v.nodeFor(i).array[i&mask].(T)
 	return t
 }
 
+// forEach calls f on each element of v in index order, stopping early if f
+// returns false.  Elements are read one leaf at a time, so each call to f
+// costs O(1) rather than the O(log n) of At.
+func (v Vector[T]) forEach(f func(int, T) bool) bool {
+	return v.forRange(0, v.cnt, f)
+}
+
+// forRange is like forEach, but only visits the elements in [start, end).
+func (v Vector[T]) forRange(start, end int, f func(int, T) bool) bool {
+	for i := start; i < end; {
+		n := v.nodeFor(i)
+		for j := i & mask; j < width && i < end; i, j = i+1, j+1 {
+			t, _ := n.array[j].(T)
+			if !f(i, t) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// forRangeReverse is like forRange, but visits the elements in [start, end)
+// in reverse index order.
+func (v Vector[T]) forRangeReverse(start, end int, f func(int, T) bool) bool {
+	for i := end - 1; i >= start; {
+		n := v.nodeFor(i)
+		for j := i & mask; j >= 0 && i >= start; i, j = i-1, j-1 {
+			t, _ := n.array[j].(T)
+			if !f(i, t) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// walkNodes calls f on every node reachable from v, including the tail.
+func (v Vector[T]) walkNodes(f func(*node[T])) {
+	var walk func(level int, n *node[T])
+	walk = func(level int, n *node[T]) {
+		f(n)
+		if level == 0 {
+			return
+		}
+
+		for _, child := range n.array {
+			if c, ok := child.(*node[T]); ok && c != nil {
+				walk(level-bits, c)
+			}
+		}
+	}
+
+	if v.root != nil {
+		walk(v.shift, v.root)
+	}
+
+	if v.tail != nil {
+		f(v.tail)
+	}
+}
+
+func (v Vector[T]) toSlice() []T {
+	ts := make([]T, 0, v.cnt)
+	v.forEach(func(_ int, t T) bool {
+		ts = append(ts, t)
+		return true
+	})
+
+	return ts
+}
+
+func (v Vector[T]) checkRange(start, end int) {
+	if start < 0 || end < start || end > v.cnt {
+		panic("slice bounds out of range")
+	}
+}
+
+// ReadAt copies elements of the Vector, starting at index start, into dst.
+// It returns the number of elements copied, which is the lesser of len(dst)
+// and v.Len()-start.  It panics if start is not in [0, v.Len()].
+func (v Vector[T]) ReadAt(dst []T, start int) int {
+	if start < 0 || start > v.cnt {
+		panic("index out of bounds")
+	}
+
+	end := min(v.cnt, start+len(dst))
+	v.forRange(start, end, func(i int, t T) bool {
+		dst[i-start] = t
+		return true
+	})
+
+	return end - start
+}
+
+// Tee calls each consumer on every element of the Vector, in index order,
+// during a single traversal.  For each element, consumers are called in the
+// order in which they were passed.
+func (v Vector[T]) Tee(consumers ...func(T)) {
+	if len(consumers) == 0 {
+		return
+	}
+
+	v.forEach(func(_ int, t T) bool {
+		for _, consume := range consumers {
+			consume(t)
+		}
+
+		return true
+	})
+}
+
+// DeepClone returns a Vector that shares no nodes with v, whose elements
+// are copies of those of v, as made by copyElem.  Operations on vectors
+// otherwise copy elements shallowly, so element types containing slices,
+// maps or pointers remain shared between versions; DeepClone is for when
+// that sharing is undesirable.
+func (v Vector[T]) DeepClone(copyElem func(T) T) Vector[T] {
+	b := NewBuilder[T]()
+	v.forEach(func(_ int, t T) bool {
+		b.Cons(copyElem(t))
+		return true
+	})
+
+	return b.Vector()
+}
+
+// Format returns the elements of v, each rendered by elem, joined by sep.
+func (v Vector[T]) Format(sep string, elem func(T) string) string {
+	var b strings.Builder
+	v.forEach(func(i int, t T) bool {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+
+		b.WriteString(elem(t))
+		return true
+	})
+
+	return b.String()
+}
+
+// CountLeading returns the number of consecutive elements at the start of
+// the Vector that satisfy pred.
+func (v Vector[T]) CountLeading(pred func(T) bool) int {
+	n := 0
+	v.forEach(func(_ int, t T) bool {
+		if !pred(t) {
+			return false
+		}
+
+		n++
+		return true
+	})
+
+	return n
+}
+
+// CountTrailing returns the number of consecutive elements at the end of
+// the Vector that satisfy pred.
+func (v Vector[T]) CountTrailing(pred func(T) bool) int {
+	n := 0
+	v.forRangeReverse(0, v.cnt, func(_ int, t T) bool {
+		if !pred(t) {
+			return false
+		}
+
+		n++
+		return true
+	})
+
+	return n
+}
+
 // Set takes a value and "associates" it to the Vector,
 // assigning it to the index.
 func (v Vector[T]) Set(index int, t T) Vector[T] {
@@ -117,7 +353,7 @@ func (v Vector[T]) Set(index int, t T) Vector[T] {
 }
 
 func (v Vector[T]) doAssoc(level int, n *node[T], i int, t T) *node[T] {
-	ret := n
+	ret := n.clone()
 	if level == 0 {
 		ret.array[i&mask] = t
 	} else {
@@ -128,6 +364,367 @@ func (v Vector[T]) doAssoc(level int, n *node[T], i int, t T) *node[T] {
 	return ret
 }
 
+// BlockRanges yields the bounds [start, end) of each leaf in the Vector, in
+// order.  Every block spans a full leaf of 32 elements, except possibly the
+// last.  Processing elements block by block keeps each unit of work within a
+// single contiguous leaf.
+func (v Vector[T]) BlockRanges() iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		for i := 0; i < v.cnt; i += width {
+			if !yield(i, min(i+width, v.cnt)) {
+				return
+			}
+		}
+	}
+}
+
+// Step yields the index and value of every step-th element, starting at
+// start and stopping before stop, in the manner of a Python slice.  If step
+// is negative, the elements are visited in descending index order, and
+// stop may be -1 to include the first element.  If the range is empty in
+// the direction of step, nothing is yielded.  It panics if step is zero,
+// or if the range is non-empty and out of bounds.
+func (v Vector[T]) Step(start, stop, step int) iter.Seq2[int, T] {
+	switch {
+	case step == 0:
+		panic("zero step")
+	case step > 0 && start < stop:
+		v.checkRange(start, stop)
+	case step < 0 && start > stop:
+		if stop < -1 || start >= v.cnt {
+			panic("slice bounds out of range")
+		}
+	}
+
+	return func(yield func(int, T) bool) {
+		var leaf *node[T]
+		for i := start; (step > 0 && i < stop) || (step < 0 && i > stop); i += step {
+			if leaf == nil || i>>bits != (i-step)>>bits {
+				leaf = v.nodeFor(i)
+			}
+
+			t, _ := leaf.array[i&mask].(T)
+			if !yield(i, t) {
+				return
+			}
+		}
+	}
+}
+
+// Slice returns a Vector containing the elements in [start, end).
+// It panics if 0 <= start <= end <= v.Len() does not hold.
+func (v Vector[T]) Slice(start, end int) Vector[T] {
+	v.checkRange(start, end)
+	if start == 0 && end == v.cnt {
+		return v
+	}
+
+	b := NewBuilder[T]()
+	v.forRange(start, end, b.cons)
+	return b.Vector()
+}
+
+// Slice3 is the analog of the full slice expression v[low:high:max].  It
+// panics unless 0 <= low <= high <= max <= v.Len(), and otherwise returns
+// v.Slice(low, high).  Because appending to a Vector never writes to shared
+// storage, max has no further effect; it is validated only so that code
+// ported from slices retains its bounds checks.
+func (v Vector[T]) Slice3(low, high, max int) Vector[T] {
+	if high > max || max > v.cnt {
+		panic("slice bounds out of range")
+	}
+
+	return v.Slice(low, high)
+}
+
+// Neighborhood returns the elements within radius of index i, i.e. the
+// range [i-radius, i+radius], clamped to the bounds of the Vector.  It
+// panics if i is out of bounds or radius is negative.
+func (v Vector[T]) Neighborhood(i, radius int) Vector[T] {
+	if i < 0 || i >= v.cnt {
+		panic("index out of bounds")
+	}
+
+	if radius < 0 {
+		panic("negative radius")
+	}
+
+	start, end := i-radius, i+radius+1
+	if start < 0 {
+		start = 0
+	}
+	if end > v.cnt || end < 0 { // end < 0 on overflow
+		end = v.cnt
+	}
+
+	return v.Slice(start, end)
+}
+
+// Cut removes the elements in [start, end) from the Vector.  It returns
+// the remaining elements, followed by the removed ones.  Both are built in
+// a single pass over v.  It panics if 0 <= start <= end <= v.Len() does not
+// hold.
+func (v Vector[T]) Cut(start, end int) (rest, removed Vector[T]) {
+	v.checkRange(start, end)
+	if start == end {
+		return v, Vector[T]{}
+	}
+
+	r, c := NewBuilder[T](), NewBuilder[T]()
+	v.forEach(func(i int, t T) bool {
+		if i >= start && i < end {
+			c.Cons(t)
+		} else {
+			r.Cons(t)
+		}
+
+		return true
+	})
+
+	return r.Vector(), c.Vector()
+}
+
+// PadLeft returns a Vector of the given length, formed by prepending copies
+// of fill to v.  If v already contains at least length elements, it is
+// returned unchanged.
+func (v Vector[T]) PadLeft(length int, fill T) Vector[T] {
+	if v.cnt >= length {
+		return v
+	}
+
+	b := NewBuilder[T]()
+	b.appendRepeat(fill, length-v.cnt)
+	v.forEach(b.cons)
+	return b.Vector()
+}
+
+// PadRight returns a Vector of the given length, formed by appending copies
+// of fill to v.  If v already contains at least length elements, it is
+// returned unchanged.
+func (v Vector[T]) PadRight(length int, fill T) Vector[T] {
+	if v.cnt >= length {
+		return v
+	}
+
+	b := v.transient()
+	b.appendRepeat(fill, length-v.cnt)
+	return b.Vector()
+}
+
+// ShiftLeft returns a Vector of the same length as v, in which the first n
+// elements have been dropped and n copies of fill appended.  It panics if
+// n is negative.
+func (v Vector[T]) ShiftLeft(n int, fill T) Vector[T] {
+	if n < 0 {
+		panic("negative shift amount")
+	}
+
+	if n == 0 || v.cnt == 0 {
+		return v
+	}
+
+	n = min(n, v.cnt)
+	b := NewBuilder[T]()
+	v.forRange(n, v.cnt, b.cons)
+	b.appendRepeat(fill, n)
+	return b.Vector()
+}
+
+// ShiftRight returns a Vector of the same length as v, in which the last n
+// elements have been dropped and n copies of fill prepended.  It panics if
+// n is negative.
+func (v Vector[T]) ShiftRight(n int, fill T) Vector[T] {
+	if n < 0 {
+		panic("negative shift amount")
+	}
+
+	if n == 0 || v.cnt == 0 {
+		return v
+	}
+
+	n = min(n, v.cnt)
+	b := NewBuilder[T]()
+	b.appendRepeat(fill, n)
+	v.forRange(0, v.cnt-n, b.cons)
+	return b.Vector()
+}
+
+// Reverse returns a copy of the Vector with its elements in reverse order.
+func (v Vector[T]) Reverse() Vector[T] {
+	if v.cnt < 2 {
+		return v
+	}
+
+	b := v.transient()
+	b.Reverse()
+	return b.Vector()
+}
+
+// Reversed returns a read-only view of v with its elements in reverse
+// order.  Unlike Reverse, it does not copy v.
+func (v Vector[T]) Reversed() ReversedView[T] {
+	return ReversedView[T]{v: v}
+}
+
+// ReversedView is a zero-copy, read-only view of a Vector in reverse
+// order.  It is obtained by calling Vector.Reversed.
+type ReversedView[T any] struct {
+	v Vector[T]
+}
+
+// Len returns the number of elements in the view.
+func (r ReversedView[T]) Len() int {
+	return r.v.cnt
+}
+
+// At returns the ith element of the view, which is the element at index
+// Len()-1-i of the underlying Vector.  It panics if i is out of bounds.
+func (r ReversedView[T]) At(i int) T {
+	if i < 0 || i >= r.v.cnt {
+		panic("index out of bounds")
+	}
+
+	return r.v.At(r.v.cnt - 1 - i)
+}
+
+// Materialize returns the contents of the view as a Vector.
+func (r ReversedView[T]) Materialize() Vector[T] {
+	return r.v.Reverse()
+}
+
+// RotateInPlaceBuilder returns a copy of the Vector rotated left by k
+// positions, so that the element at index k comes first.  Negative values
+// of k rotate right, and k is taken modulo v.Len().  The rotation is done
+// on a single Builder by reversing [0, k), then [k, n), then the whole
+// vector, which moves each element twice but allocates only one trie.
+func (v Vector[T]) RotateInPlaceBuilder(k int) Vector[T] {
+	if v.cnt == 0 {
+		return v
+	}
+
+	if k %= v.cnt; k < 0 {
+		k += v.cnt
+	}
+
+	if k == 0 {
+		return v
+	}
+
+	b := v.transient()
+	b.reverseRange(0, k)
+	b.reverseRange(k, b.cnt)
+	b.Reverse()
+	return b.Vector()
+}
+
+// ReverseRange returns a copy of the Vector in which the elements in
+// [start, end) appear in reverse order.  Elements outside of the range
+// are unchanged, and the leaves holding them are shared with v.
+func (v Vector[T]) ReverseRange(start, end int) Vector[T] {
+	v.checkRange(start, end)
+	if end-start < 2 {
+		return v
+	}
+
+	b := v.transient()
+	b.reverseRange(start, end)
+	return b.Vector()
+}
+
+// Update returns a copy of the Vector in which the element at index has been
+// replaced by f applied to it.  Unlike a call to At followed by Set, Update
+// walks the trie only once.  It panics if index is out of bounds.
+func (v Vector[T]) Update(index int, f func(T) T) Vector[T] {
+	if index < 0 || index >= v.cnt {
+		panic("index out of bounds")
+	}
+
+	if index >= v.tailoff() {
+		newTail := v.tail.clone()
+		t, _ := newTail.array[index&mask].(T)
+		newTail.array[index&mask] = f(t)
+		return Vector[T]{
+			cnt:   v.cnt,
+			shift: v.shift,
+			root:  v.root,
+			tail:  newTail,
+		}
+	}
+
+	return Vector[T]{
+		cnt:   v.cnt,
+		shift: v.shift,
+		root:  v.doUpdate(v.shift, v.root, index, f),
+		tail:  v.tail,
+	}
+}
+
+// UpdateWhere returns a copy of the Vector in which every element that
+// satisfies pred has been replaced by f applied to it.  Leaves without any
+// matching elements are shared with v, and v itself is returned if nothing
+// matched.
+func (v Vector[T]) UpdateWhere(pred func(T) bool, f func(T) T) Vector[T] {
+	var b *Builder[T]
+	v.forEach(func(i int, t T) bool {
+		if pred(t) {
+			if b == nil {
+				b = v.transient()
+			}
+
+			b.Set(i, f(t))
+		}
+
+		return true
+	})
+
+	if b == nil {
+		return v
+	}
+
+	return b.Vector()
+}
+
+// SetFrom returns a copy of the Vector in which, for each i, the element
+// at indices.At(i) has been replaced by values.At(i).  Updates are applied
+// in order, so later writes to the same index win.  It panics if indices
+// and values have different lengths, or if any index is out of bounds, in
+// which case no update is applied.
+func (v Vector[T]) SetFrom(indices Vector[int], values Vector[T]) Vector[T] {
+	checkSameLen(indices, values)
+	indices.forEach(func(_ int, i int) bool {
+		if i < 0 || i >= v.cnt {
+			panic("index out of bounds")
+		}
+
+		return true
+	})
+
+	if indices.cnt == 0 {
+		return v
+	}
+
+	b := v.transient()
+	forEach2(indices, values, func(_ int, i int, t T) bool {
+		b.Set(i, t)
+		return true
+	})
+
+	return b.Vector()
+}
+
+func (v Vector[T]) doUpdate(level int, n *node[T], i int, f func(T) T) *node[T] {
+	ret := n.clone()
+	if level == 0 {
+		t, _ := ret.array[i&mask].(T)
+		ret.array[i&mask] = f(t)
+	} else {
+		subidx := (i >> level) & mask
+		ret.array[subidx] = v.doUpdate(level-bits, n.array[subidx].(*node[T]), i, f)
+	}
+
+	return ret
+}
+
 // Append values to the Vector.
 func (v Vector[T]) Append(ts ...T) Vector[T] {
 	switch len(ts) {
@@ -145,8 +742,180 @@ func (v Vector[T]) Append(ts ...T) Vector[T] {
 	}
 }
 
+// AppendBounded appends as many values to the Vector as will fit without
+// its length exceeding max.  If any values were dropped, the returned error
+// wraps ErrCapacityExceeded and reports how many.
+func (v Vector[T]) AppendBounded(max int, ts ...T) (Vector[T], error) {
+	room := max - v.cnt
+	if room >= len(ts) {
+		return v.Append(ts...), nil
+	}
+
+	if room < 0 {
+		room = 0
+	}
+
+	return v.Append(ts[:room]...), fmt.Errorf("%w: dropped %d of %d elements",
+		ErrCapacityExceeded, len(ts)-room, len(ts))
+}
+
+// PushBounded appends item to the Vector, then drops elements from the
+// front as needed for its length not to exceed max, as in a fixed-capacity
+// ring buffer.  It panics if max <= 0.
+//
+// Elements cannot be removed from the front of a Vector in place, so once
+// the bound is reached every call rebuilds the Vector from the retained
+// elements.  Whole leaves of v are reused when the retained elements start
+// on a leaf boundary, but otherwise they are copied, so a call costs O(max)
+// time and allocation in general.  Callers pushing many elements into a
+// large window should prefer a mutable ring buffer.
+func (v Vector[T]) PushBounded(max int, item T) Vector[T] {
+	if max <= 0 {
+		panic("non-positive bound")
+	}
+
+	if v.cnt < max {
+		return v.cons(item)
+	}
+
+	b := NewBuilder[T]()
+	b.appendRange(v, v.cnt-max+1, v.cnt)
+	b.Cons(item)
+	return b.Vector()
+}
+
+// AppendRepeat appends n copies of t to the Vector.  Full leaves of
+// repeated values share a single node.  If n <= 0, the receiver is
+// returned unchanged.
+func (v Vector[T]) AppendRepeat(t T, n int) Vector[T] {
+	if n <= 0 {
+		return v
+	}
+
+	b := v.transient()
+	b.appendRepeat(t, n)
+	return b.Vector()
+}
+
+// AppendShared merges other into v, for the case where v and other were
+// both produced by appending to a common ancestor.  It returns v followed
+// by the elements of other that come after the longest prefix that v and
+// other share by pointer identity, so that merging two branches of a
+// versioned vector does not duplicate their shared history.
+//
+// Only whole leaves count as shared.  Elements that the two vectors hold
+// in separate leaves are copied from other even if they are equal; in
+// particular, appending to a vector copies its tail, so up to 32 elements
+// of the common ancestor may appear twice in the result.  Subtrees shared
+// by both vectors are skipped without being visited, and the leaves of
+// the divergent region of other are grafted into the result rather than
+// copied whenever they line up with the leaves of v.  If v and other are
+// unrelated, the result is v followed by all of other.
+func (v Vector[T]) AppendShared(other Vector[T]) Vector[T] {
+	if v.cnt == 0 {
+		return other
+	}
+
+	n := v.sharedLeaves(other)
+	if n == other.cnt {
+		return v
+	}
+
+	b := v.transient()
+	b.appendRange(other, n, other.cnt)
+	return b.Vector()
+}
+
+// sharedLeaves returns the length of the longest prefix of v that is
+// stored in leaves that other shares by pointer identity, including the
+// tail.  Subtrees that v and other share are not descended into, so for
+// vectors derived from one another this costs O(log n).
+func (v Vector[T]) sharedLeaves(other Vector[T]) int {
+	limit := min(v.tailoff(), other.tailoff())
+
+	var walk func(level, start int, a, b *node[T]) int
+	walk = func(level, start int, a, b *node[T]) int {
+		if a == b {
+			return min(1<<(level+bits), limit-start)
+		}
+
+		if level == 0 {
+			return 0
+		}
+
+		var n int
+		for i := 0; i < width && start+n < limit; i++ {
+			m := walk(level-bits, start+n, a.array[i].(*node[T]), b.array[i].(*node[T]))
+			if n += m; m < 1<<level {
+				break
+			}
+		}
+
+		return n
+	}
+
+	var n int
+	if limit > 0 {
+		// A trie that has grown taller keeps its old root as its
+		// leftmost child.
+		a, b := v.root, other.root
+		for level := v.shift; level > other.shift; level -= bits {
+			a = a.array[0].(*node[T])
+		}
+		for level := other.shift; level > v.shift; level -= bits {
+			b = b.array[0].(*node[T])
+		}
+
+		n = walk(min(v.shift, other.shift), 0, a, b)
+	}
+
+	// The leaf after the trie of the shorter vector may be the tail of
+	// one or both of them.
+	if n == limit && n < min(v.cnt, other.cnt) && v.nodeFor(n) == other.nodeFor(n) {
+		n = min(n+width, v.cnt, other.cnt)
+	}
+
+	return n
+}
+
+// Grow returns a Vector with the same contents as v, whose trie is deep
+// enough to hold at least n more elements without its depth having to
+// increase, analogous to slices.Grow.  Appending beyond that point remains
+// valid.  Growing allocates only the new root nodes; the elements are
+// shared with v.  It panics if n is negative.
+func (v Vector[T]) Grow(n int) Vector[T] {
+	if n < 0 {
+		panic("negative count")
+	}
+
+	if n == 0 {
+		return v
+	}
+
+	if v.IsZero() {
+		v = newVector[T]()
+	}
+
+	for 1<<(v.shift+bits)+width < v.cnt+n {
+		v.root = newPathNode(nil, v.root)
+		v.shift += bits
+	}
+
+	return v
+}
+
+// AppendIf appends values to the Vector if cond is true.  Otherwise, it
+// returns the receiver unchanged.
+func (v Vector[T]) AppendIf(cond bool, ts ...T) Vector[T] {
+	if !cond {
+		return v
+	}
+
+	return v.Append(ts...)
+}
+
 func (v Vector[T]) cons(t T) Vector[T] {
-	if v == (Vector[T]{}) {
+	if v.IsZero() {
 		v = newVector[T]()
 	}
 
@@ -173,7 +942,7 @@ func (v Vector[T]) cons(t T) Vector[T] {
 	if (v.cnt >> bits) > (1 << v.shift) {
 		newRoot.len += 2
 		newRoot.array[0] = v.root
-		newRoot.array[1] = newPath(v.shift, tailNode)
+		newRoot.array[1] = newPath(nil, v.shift, tailNode)
 		newShift += bits
 	} else {
 		newRoot = v.pushTail(v.shift, v.root, tailNode)
@@ -187,12 +956,12 @@ func (v Vector[T]) cons(t T) Vector[T] {
 	}
 }
 
-func newPath[T any](level int, n *node[T]) *node[T] {
+func newPath[T any](edit *owner, level int, n *node[T]) *node[T] {
 	if level <= 0 {
 		return n
 	}
 
-	return newPathNode(newPath(level-bits, n))
+	return newPathNode(edit, newPath(edit, level-bits, n))
 }
 
 func (v Vector[T]) pushTail(level int, parent, tailNode *node[T]) *node[T] {
@@ -212,7 +981,7 @@ func (v Vector[T]) pushTail(level int, parent, tailNode *node[T]) *node[T] {
 		if child := parent.array[subidx]; child != nil {
 			nodeToInsert = v.pushTail(level-bits, child.(*node[T]), tailNode)
 		} else {
-			nodeToInsert = newPath(level-bits, tailNode)
+			nodeToInsert = newPath(nil, level-bits, tailNode)
 		}
 	}
 
@@ -245,11 +1014,9 @@ func (v Vector[T]) Pop() Vector[T] {
 	newShift := v.shift
 	if newRoot == nil {
 		newRoot = &node[T]{}
-	}
-	if v.shift > bits && newRoot.array[1] == nil {
-		if newRoot.array[0] == nil {
-			newRoot = &node[T]{}
-		}
+		newShift = bits
+	} else if v.shift > bits && newRoot.array[1] == nil {
+		newRoot = newRoot.array[0].(*node[T])
 		newShift -= bits
 	}
 
@@ -261,6 +1028,33 @@ func (v Vector[T]) Pop() Vector[T] {
 	}
 }
 
+// PopNValues returns a copy of the Vector without its last n elements,
+// along with the removed elements in their original order.  If n exceeds
+// the length of the Vector, all elements are removed.
+func (v Vector[T]) PopNValues(n int) (Vector[T], []T) {
+	n = max(0, min(n, v.cnt))
+	if n == 0 {
+		return v, nil
+	}
+
+	ts := make([]T, 0, n)
+	v.forRange(v.cnt-n, v.cnt, func(_ int, t T) bool {
+		ts = append(ts, t)
+		return true
+	})
+
+	if n == v.cnt {
+		return Vector[T]{}, ts
+	}
+
+	b := v.transient()
+	for i := 0; i < n; i++ {
+		b.Pop()
+	}
+
+	return b.Vector(), ts
+}
+
 func (v Vector[T]) popTail(level int, n *node[T]) *node[T] {
 	subidx := ((v.cnt - 2) >> level) & mask
 	if level > bits {
@@ -270,8 +1064,11 @@ func (v Vector[T]) popTail(level int, n *node[T]) *node[T] {
 		}
 
 		ret := n.clone()
-		ret.array[subidx] = newChild
-		// ret.len++
+		if newChild == nil {
+			ret.array[subidx] = nil // avoid storing a typed nil
+		} else {
+			ret.array[subidx] = newChild
+		}
 		return ret
 
 	} else if subidx == 0 {
@@ -279,7 +1076,7 @@ func (v Vector[T]) popTail(level int, n *node[T]) *node[T] {
 	}
 
 	ret := n.clone()
-	ret.array[subidx] = node[T]{}
+	ret.array[subidx] = nil
 	return ret
 }
 
@@ -289,22 +1086,75 @@ func (v Vector[T]) popTail(level int, n *node[T]) *node[T] {
 type Builder[T any] struct {
 	cnt, shift int
 	root, tail *node[T]
+	edit       *owner
 }
 
 func NewBuilder[T any]() *Builder[T] {
-	vec := newVector[T]()
-	return (*Builder[T])(&vec)
+	edit := new(owner)
+	return &Builder[T]{
+		shift: bits,
+		root:  &node[T]{edit: edit},
+		tail:  &node[T]{edit: edit},
+		edit:  edit,
+	}
 }
 
 // Vector finalizes the builder into a Vector.
 // Users MUST NOT mutate t after a call to Vector.
-func (t Builder[T]) Vector() Vector[T] { return (Vector[T])(t) }
+func (t Builder[T]) Vector() Vector[T] {
+	return Vector[T]{
+		cnt:   t.cnt,
+		shift: t.shift,
+		root:  t.root,
+		tail:  t.tail,
+	}
+}
 
-func (t Builder[T]) tailoff() int { return (Vector[T])(t).tailoff() }
+// Snapshot returns the current contents of the builder as a Vector, and
+// unlike Vector, leaves t free to be mutated afterwards.  It does so by
+// giving t a new edit token, so that the nodes held by the snapshot are no
+// longer owned by t.  The tail is copied immediately; thereafter, the first
+// write to each path in the trie copies that path, as in Vector.Set.  A
+// snapshot therefore costs O(1), plus O(log n) for each distinct leaf that
+// is subsequently written.
+func (t *Builder[T]) Snapshot() Vector[T] {
+	v := t.Vector()
+	t.edit = new(owner)
+	t.tail = t.tail.editable(t.edit)
+	return v
+}
+
+// Values returns an iterator over the builder's elements in index order.
+// The iterator reflects the builder's contents at the time iteration
+// starts.  The behavior of mutating t during iteration is undefined.
+func (t *Builder[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		t.Vector().forEach(func(_ int, val T) bool {
+			return yield(val)
+		})
+	}
+}
+
+func (t Builder[T]) tailoff() int { return t.Vector().tailoff() }
 
 // Count the number of elements in the vector.
 func (t *Builder[T]) Len() int { return t.cnt }
 
+// Cap returns the number of elements the builder can hold before the
+// depth of its trie must increase.
+func (t *Builder[T]) Cap() int { return 1<<(t.shift+bits) + width }
+
+// EnsureCapacity grows the builder's trie so that it can hold at least n
+// elements in total without increasing its depth.  Growing the trie
+// up-front avoids reallocating the root each time it overflows.  Appending
+// beyond n elements remains valid.
+func (t *Builder[T]) EnsureCapacity(n int) {
+	for t.Cap() < n {
+		t.root = newPathNode(t.edit, t.root)
+		t.shift += bits
+	}
+}
+
 // Append values to the vector
 func (t *Builder[T]) Append(ts ...T) {
 	for _, val := range ts {
@@ -312,6 +1162,54 @@ func (t *Builder[T]) Append(ts ...T) {
 	}
 }
 
+// With appends values to the vector and returns t, so that calls can be
+// chained:
+//
+//	v := vector.NewBuilder[int]().With(1, 2).With(3).Vector()
+func (t *Builder[T]) With(ts ...T) *Builder[T] {
+	t.Append(ts...)
+	return t
+}
+
+// AppendSeqs drains each of seqs into the vector, in argument order.
+func (t *Builder[T]) AppendSeqs(seqs ...iter.Seq[T]) {
+	for _, seq := range seqs {
+		for val := range seq {
+			t.Cons(val)
+		}
+	}
+}
+
+// AppendFunc appends n values to the vector, the ith of which is given by
+// f(i).  Values are written directly into the tail a leaf at a time, which
+// is faster than calling Cons in a loop.
+func (t *Builder[T]) AppendFunc(n int, f func(i int) T) {
+	for i := 0; i < n; {
+		off := t.cnt - t.tailoff()
+		if off == width { // full tail; push into trie
+			t.Cons(f(i))
+			i++
+			continue
+		}
+
+		k := min(width-off, n-i)
+		for j := 0; j < k; j++ {
+			t.tail.array[off+j] = f(i + j)
+		}
+
+		t.tail.len += k
+		t.cnt += k
+		i += k
+	}
+}
+
+// AppendIf appends values to the vector if cond is true.
+func (t *Builder[T]) AppendIf(cond bool, ts ...T) {
+	if cond {
+		t.Append(ts...)
+	}
+}
+
 func (t *Builder[T]) Cons(val T) {
 	// room in tail?
 	if t.cnt-t.tailoff() < 32 {
@@ -322,24 +1220,90 @@ func (t *Builder[T]) Cons(val T) {
 	}
 
 	// full tail; push into trie
-	newRoot := &node[T]{}
-	tailNode := t.tail.clone()
+	t.pushFullTail()
 	t.tail = newValueNode(val)
-	newShift := t.shift
+	t.tail.edit = t.edit
+	t.cnt++
+}
 
+// pushFullTail inserts the tail into the trie, growing the root if needed.
+// The caller is responsible for replacing the tail afterwards.
+func (t *Builder[T]) pushFullTail() {
 	// overflow root?
 	if (t.cnt >> bits) > (1 << t.shift) {
-		newRoot.len += 2
+		newRoot := &node[T]{edit: t.edit, len: 2}
 		newRoot.array[0] = t.root
-		newRoot.array[1] = newPath(t.shift, tailNode)
-		newShift += 5
+		newRoot.array[1] = newPath(t.edit, t.shift, t.tail)
+		t.root = newRoot
+		t.shift += bits
 	} else {
-		newRoot = t.pushTail(t.shift, t.root, tailNode)
+		t.root = t.pushTail(t.shift, t.root, t.tail)
 	}
+}
 
-	t.root = newRoot
-	t.shift = newShift
-	t.cnt++
+// appendRepeat appends n copies of val.  Full leaves are identical, so a
+// single unowned leaf is shared between all of them; any later write to
+// one of them copies it first.
+func (t *Builder[T]) appendRepeat(val T, n int) {
+	fill := func(int) T { return val }
+
+	k := min(n, width-(t.cnt-t.tailoff()))
+	t.AppendFunc(k, fill)
+	if n -= k; n == 0 {
+		return
+	}
+
+	// The tail is now full.  Push it, and stand in the shared leaf as the
+	// new tail.  At least one element is left for AppendFunc, so the final
+	// tail is always owned by t.
+	var leaf *node[T]
+	for ; n > width; n -= width {
+		if leaf == nil {
+			leaf = newValueNode(make([]T, width)...)
+			for i := range leaf.array {
+				leaf.array[i] = val
+			}
+		}
+
+		t.pushFullTail()
+		t.tail = leaf
+		t.cnt += width
+	}
+
+	t.AppendFunc(n, fill)
+}
+
+// appendRange appends the elements v[start:end].  Whenever the leaves of
+// v line up with those of t, full leaves are grafted into t as-is rather
+// than copied.  The last leaf is always copied, so the final tail is owned
+// by t.
+func (t *Builder[T]) appendRange(v Vector[T], start, end int) {
+	i := start
+	if (t.cnt-start)&mask == 0 {
+		// Fill t's tail up to the next leaf boundary of v; grafting needs
+		// a full tail to push.
+		lead := (width - i&mask) & mask
+		if t.cnt+lead == 0 {
+			lead = width
+		}
+
+		lead = min(lead, end-i)
+		v.forRange(i, i+lead, t.cons)
+
+		for i += lead; i+width < end; i += width {
+			t.pushFullTail()
+			t.tail = v.nodeFor(i)
+			t.cnt += width
+		}
+	}
+
+	v.forRange(i, end, t.cons)
+}
+
+// cons is a callback-friendly version of Cons, suitable for forEach.
+func (t *Builder[T]) cons(_ int, val T) bool {
+	t.Cons(val)
+	return true
 }
 
 func (t *Builder[T]) pushTail(level int, parent, tailNode *node[T]) *node[T] {
@@ -349,7 +1313,7 @@ func (t *Builder[T]) pushTail(level int, parent, tailNode *node[T]) *node[T] {
 	//return  nodeToInsert placed in parent
 
 	subidx := ((t.cnt - 1) >> level) & mask
-	ret := parent // mutable; don't clone
+	ret := parent.editable(t.edit)
 	var nodeToInsert *node[T]
 	if level == bits {
 		nodeToInsert = tailNode
@@ -357,7 +1321,7 @@ func (t *Builder[T]) pushTail(level int, parent, tailNode *node[T]) *node[T] {
 		if child := parent.array[subidx]; child != nil {
 			nodeToInsert = t.pushTail(level-bits, child.(*node[T]), tailNode)
 		} else {
-			nodeToInsert = newPath(level-bits, tailNode)
+			nodeToInsert = newPath(t.edit, level-bits, tailNode)
 		}
 	}
 
@@ -365,7 +1329,139 @@ func (t *Builder[T]) pushTail(level int, parent, tailNode *node[T]) *node[T] {
 	return ret
 }
 
+// Set assigns val to index i.  If i == Len(), val is appended.
+func (t *Builder[T]) Set(i int, val T) {
+	if i >= 0 && i < t.cnt {
+		if i >= t.tailoff() {
+			t.tail.array[i&mask] = val
+		} else {
+			t.root = t.doAssoc(t.shift, t.root, i, val)
+		}
+
+		return
+	}
+
+	if i == t.cnt {
+		t.Cons(val)
+		return
+	}
+
+	panic("index out of bounds")
+}
+
+func (t *Builder[T]) doAssoc(level int, n *node[T], i int, val T) *node[T] {
+	ret := n.editable(t.edit)
+	if level == 0 {
+		ret.array[i&mask] = val
+	} else {
+		subidx := (i >> level) & mask
+		ret.array[subidx] = t.doAssoc(level-bits, ret.array[subidx].(*node[T]), i, val)
+	}
+
+	return ret
+}
+
+// Reverse reverses the order of the elements in the vector, in place.  It
+// runs in O(n) time, and does not allocate unless the builder shares nodes
+// with a Vector, in which case each shared node is copied once.
+func (t *Builder[T]) Reverse() {
+	t.reverseRange(0, t.cnt)
+}
+
+// reverseRange reverses the elements in [start, end), in place.
+func (t *Builder[T]) reverseRange(start, end int) {
+	for i, j := start, end-1; i < j; {
+		a, b := t.editableNodeFor(i), t.editableNodeFor(j)
+		for sameLeaves := true; sameLeaves && i < j; {
+			a.array[i&mask], b.array[j&mask] = b.array[j&mask], a.array[i&mask]
+			i, j = i+1, j-1
+			sameLeaves = i&mask != 0 && j&mask != mask
+		}
+	}
+}
+
+// editableNodeFor returns the leaf containing index i, first copying any
+// nodes on the path to it that are not owned by t.
+func (t *Builder[T]) editableNodeFor(i int) *node[T] {
+	if i >= t.tailoff() {
+		return t.tail
+	}
+
+	t.root = t.root.editable(t.edit)
+	n := t.root
+	for level := t.shift; level > 0; level -= bits {
+		subidx := (i >> level) & mask
+		child := n.array[subidx].(*node[T]).editable(t.edit)
+		n.array[subidx] = child
+		n = child
+	}
+
+	return n
+}
+
+// Pop removes the last element from the vector.  Popping an empty vector
+// is a no-op.
+func (t *Builder[T]) Pop() {
+	if t.cnt == 0 {
+		return
+	}
+
+	// len(tail) > 1 ?
+	if t.cnt == 1 || t.cnt-t.tailoff() > 1 {
+		t.cnt--
+		t.tail.array[t.cnt&mask] = nil
+		t.tail.len--
+		return
+	}
+
+	newTail := t.Vector().nodeFor(t.cnt - 2).editable(t.edit)
+
+	newRoot := t.popTail(t.shift, t.root)
+	if newRoot == nil {
+		newRoot = &node[T]{edit: t.edit}
+		t.shift = bits
+	} else if t.shift > bits && newRoot.array[1] == nil {
+		newRoot = newRoot.array[0].(*node[T])
+		t.shift -= bits
+	}
+
+	t.root = newRoot
+	t.tail = newTail
+	t.cnt--
+}
+
+func (t *Builder[T]) popTail(level int, n *node[T]) *node[T] {
+	subidx := ((t.cnt - 2) >> level) & mask
+	if level > bits {
+		newChild := t.popTail(level-bits, n.array[subidx].(*node[T]))
+		if newChild == nil && subidx == 0 {
+			return nil
+		}
+
+		ret := n.editable(t.edit)
+		if newChild == nil {
+			ret.array[subidx] = nil // avoid storing a typed nil
+		} else {
+			ret.array[subidx] = newChild
+		}
+		return ret
+
+	} else if subidx == 0 {
+		return nil
+	}
+
+	ret := n.editable(t.edit)
+	ret.array[subidx] = nil
+	return ret
+}
+
+// owner identifies the Builder that is allowed to mutate a node in place.
+// Nodes with any other owner are treated as immutable, and are copied
+// before they are modified.
+type owner struct{ _ byte } // non-zero size ensures distinct addresses
+
 type node[T any] struct {
+	edit  *owner
 	len   int
 	array [width]any
 }
@@ -379,8 +1475,8 @@ func newValueNode[T any](vs ...T) *node[T] {
 	return n
 }
 
-func newPathNode[T any](n *node[T]) *node[T] {
-	out := &node[T]{len: 1}
+func newPathNode[T any](edit *owner, n *node[T]) *node[T] {
+	out := &node[T]{edit: edit, len: 1}
 	out.array[0] = n
 	return out
 }
@@ -391,3 +1487,14 @@ func (n *node[T]) clone() *node[T] {
 		array: n.array,
 	}
 }
+
+// editable returns n if it is owned by edit, else an owned copy of n.
+func (n *node[T]) editable(edit *owner) *node[T] {
+	if n.edit == edit {
+		return n
+	}
+
+	ret := n.clone()
+	ret.edit = edit
+	return ret
+}
@@ -1,6 +1,11 @@
 package vector_test
 
 import (
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/lthibault/vector"
@@ -148,3 +153,1234 @@ func TestBuilder(t *testing.T) {
 		require.Zero(t, v, "should be zero-value vector")
 	})
 }
+
+func TestReverseRange(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i
+	}
+
+	v := vector.New(is...)
+
+	t.Run("Middle", func(t *testing.T) {
+		const start, end = 100, 3000
+
+		r := v.ReverseRange(start, end)
+		require.Equal(t, n, r.Len(), "should preserve length")
+
+		for i := 0; i < n; i++ {
+			want := i
+			if i >= start && i < end {
+				want = start + end - 1 - i
+			}
+			assert.Equal(t, want, r.At(i), "element %d", i)
+		}
+
+		assert.Equal(t, 100, v.At(100), "should not mutate receiver")
+		assert.GreaterOrEqual(t, vector.SharedNodes(v, r), start/32+(n-end)/32,
+			"should share the leaves outside the range")
+	})
+
+	t.Run("Whole", func(t *testing.T) {
+		r := v.ReverseRange(0, n)
+		for i := 0; i < n; i++ {
+			assert.Equal(t, n-1-i, r.At(i))
+		}
+	})
+
+	t.Run("Trivial", func(t *testing.T) {
+		assert.Equal(t, v, v.ReverseRange(10, 10), "empty range should no-op")
+		assert.Equal(t, v, v.ReverseRange(10, 11), "singleton range should no-op")
+	})
+
+	t.Run("OutOfBounds", func(t *testing.T) {
+		assert.Panics(t, func() { v.ReverseRange(-1, 10) },
+			"should panic when out of bounds")
+		assert.Panics(t, func() { v.ReverseRange(10, 9) },
+			"should panic when start > end")
+		assert.Panics(t, func() { v.ReverseRange(0, n+1) },
+			"should panic when out of bounds")
+	})
+}
+
+func TestPopAppend(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	var v vector.Vector[int]
+	for i := 0; i < n; i++ {
+		v = v.Append(i)
+	}
+
+	// shrink the trie by one level, then grow it back
+	for v.Len() > 1000 {
+		v = v.Pop()
+	}
+
+	for i := 1000; i < n; i++ {
+		v = v.Append(i)
+	}
+
+	require.Equal(t, n, v.Len(), "should contain %d elements", n)
+	for i := 0; i < n; i++ {
+		assert.Equal(t, i, v.At(i))
+	}
+}
+
+func TestBuilderCapacity(t *testing.T) {
+	t.Parallel()
+
+	const n = 40000
+
+	b := vector.NewBuilder[int]()
+	assert.Equal(t, 1056, b.Cap(), "should report capacity at depth 1")
+
+	b.EnsureCapacity(n / 2)
+	assert.GreaterOrEqual(t, b.Cap(), n/2, "should grow capacity")
+
+	capacity := b.Cap()
+	b.EnsureCapacity(10)
+	assert.Equal(t, capacity, b.Cap(), "should not shrink capacity")
+
+	for i := 0; i < n; i++ {
+		b.Append(i)
+	}
+
+	v := b.Vector()
+	require.Equal(t, n, v.Len(), "should append past ensured capacity")
+	for i := 0; i < n; i++ {
+		require.Equal(t, i, v.At(i))
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		v = v.Pop()
+		require.Equal(t, i, v.Len())
+		if i > 0 {
+			require.Equal(t, i-1, v.At(i-1))
+		}
+	}
+
+	require.Zero(t, v, "should be zero-value vector")
+}
+
+func TestSlice(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i
+	}
+
+	v := vector.New(is...)
+
+	s := v.Slice(100, 3000)
+	require.Equal(t, 2900, s.Len(), "should contain 2900 elements")
+	for i := 0; i < s.Len(); i++ {
+		assert.Equal(t, i+100, s.At(i))
+	}
+
+	assert.Equal(t, v, v.Slice(0, n), "full slice should return receiver")
+	assert.Zero(t, v.Slice(10, 10).Len(), "empty slice should have zero length")
+
+	assert.Panics(t, func() { v.Slice(-1, 10) },
+		"should panic when out of bounds")
+	assert.Panics(t, func() { v.Slice(10, 9) },
+		"should panic when start > end")
+	assert.Panics(t, func() { v.Slice(0, n+1) },
+		"should panic when out of bounds")
+}
+
+func TestNeighborhood(t *testing.T) {
+	t.Parallel()
+
+	v := vector.New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+
+	for _, tt := range []struct {
+		name      string
+		i, radius int
+		want      []int
+	}{
+		{"Middle", 5, 2, []int{3, 4, 5, 6, 7}},
+		{"ClampStart", 1, 3, []int{0, 1, 2, 3, 4}},
+		{"ClampEnd", 8, 3, []int{5, 6, 7, 8, 9}},
+		{"ClampBoth", 5, 100, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{"ZeroRadius", 5, 0, []int{5}},
+	} {
+		n := v.Neighborhood(tt.i, tt.radius)
+		require.Equal(t, len(tt.want), n.Len(), tt.name)
+		for i, want := range tt.want {
+			assert.Equal(t, want, n.At(i), tt.name)
+		}
+	}
+
+	assert.Panics(t, func() { v.Neighborhood(-1, 1) },
+		"should panic when out of bounds")
+	assert.Panics(t, func() { v.Neighborhood(10, 1) },
+		"should panic when out of bounds")
+	assert.Panics(t, func() { v.Neighborhood(5, -1) },
+		"should panic when radius is negative")
+}
+
+func TestAppendBranches(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	// Both branches push their tails into the same internal node of base.
+	base := vector.New[int]()
+	for i := 0; i < 32*32+5*32+10; i++ {
+		base = base.Append(i)
+	}
+
+	xs, ys := make([]int, n), make([]int, n)
+	for i := range xs {
+		xs[i], ys[i] = i, -i
+	}
+
+	a := base.Append(xs...)
+	b := base.Append(ys...)
+
+	for i := 0; i < n; i++ {
+		require.Equal(t, xs[i], a.At(base.Len()+i), "appending to base should not modify a")
+		require.Equal(t, ys[i], b.At(base.Len()+i))
+	}
+}
+
+func TestSetImmutable(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i
+	}
+
+	v := vector.New(is...)
+	for i := 0; i < n; i++ {
+		v.Set(i, -1)
+	}
+
+	for i := 0; i < n; i++ {
+		require.Equal(t, i, v.At(i), "Set should not mutate receiver")
+	}
+}
+
+func BenchmarkRotate(b *testing.B) {
+	for _, n := range []int{1 << 10, 1 << 15, 1 << 20} {
+		v := vector.Iota(0, n)
+		k := n/3 + 1 // not leaf-aligned
+
+		b.Run(fmt.Sprintf("InPlaceBuilder/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = v.RotateInPlaceBuilder(k)
+			}
+		})
+
+		b.Run(fmt.Sprintf("SliceAppend/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			head := make([]int, k)
+			for i := 0; i < b.N; i++ {
+				v.ReadAt(head, 0)
+				_ = v.Slice(k, n).Append(head...)
+			}
+		})
+	}
+}
+
+func BenchmarkPushBounded(b *testing.B) {
+	for _, bound := range []int{32, 1 << 10, 1 << 15} {
+		v := vector.Iota(0, bound)
+
+		b.Run(fmt.Sprintf("%d", bound), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				v = v.PushBounded(bound, i)
+			}
+		})
+	}
+}
+
+func TestBlockRanges(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096 + 10
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i
+	}
+
+	v := vector.New(is...)
+
+	var next int
+	for start, end := range v.BlockRanges() {
+		require.Equal(t, next, start, "blocks should be contiguous")
+		require.Zero(t, start%32, "blocks should be leaf-aligned")
+		require.LessOrEqual(t, end-start, 32, "blocks should not span leaves")
+		next = end
+	}
+
+	assert.Equal(t, n, next, "blocks should cover the whole vector")
+
+	var count int
+	for range v.BlockRanges() {
+		if count++; count == 3 {
+			break
+		}
+	}
+	assert.Equal(t, 3, count, "should stop early")
+
+	for range (vector.Vector[int]{}).BlockRanges() {
+		t.Error("empty vector should yield no blocks")
+	}
+}
+
+func TestAppendIf(t *testing.T) {
+	t.Parallel()
+
+	v := vector.New(1, 2, 3)
+	assert.Equal(t, v, v.AppendIf(false, 4, 5), "should no-op when cond is false")
+
+	v2 := v.AppendIf(true, 4, 5)
+	require.Equal(t, 5, v2.Len(), "should append when cond is true")
+	assert.Equal(t, 5, v2.At(4))
+
+	b := vector.NewBuilder[int]()
+	b.AppendIf(false, 1, 2)
+	b.AppendIf(true, 3, 4)
+	require.Equal(t, 2, b.Len(), "builder should append only when cond is true")
+	assert.Equal(t, 3, b.Vector().At(0))
+}
+
+func TestAppendFunc(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	b := vector.NewBuilder[int]()
+	b.Append(-3, -2, -1)
+	b.AppendFunc(n, func(i int) int { return i })
+	b.AppendFunc(0, func(int) int { panic("should not be called") })
+
+	v := b.Vector()
+	require.Equal(t, n+3, v.Len(), "should append %d elements", n)
+	for i := 0; i < v.Len(); i++ {
+		require.Equal(t, i-3, v.At(i))
+	}
+
+	v = v.Append(n)
+	assert.Equal(t, n, v.At(n+3), "should remain appendable")
+}
+
+func TestPad(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i + 1
+	}
+
+	v := vector.New(is...)
+
+	t.Run("Left", func(t *testing.T) {
+		p := v.PadLeft(n+100, 0)
+		require.Equal(t, n+100, p.Len(), "should pad to length")
+		for i := 0; i < p.Len(); i++ {
+			want := 0
+			if i >= 100 {
+				want = i - 99
+			}
+			require.Equal(t, want, p.At(i))
+		}
+
+		assert.Equal(t, v, v.PadLeft(n, 0), "should no-op when long enough")
+	})
+
+	t.Run("Right", func(t *testing.T) {
+		p := v.PadRight(n+100, 0)
+		require.Equal(t, n+100, p.Len(), "should pad to length")
+		for i := 0; i < p.Len(); i++ {
+			want := 0
+			if i < n {
+				want = i + 1
+			}
+			require.Equal(t, want, p.At(i))
+		}
+
+		assert.Equal(t, n, v.Len(), "should not mutate receiver")
+		assert.Equal(t, v, v.PadRight(10, 0), "should no-op when long enough")
+	})
+
+	t.Run("ZeroValue", func(t *testing.T) {
+		var v vector.Vector[int]
+		assert.Equal(t, 3, v.PadLeft(3, 7).Len())
+		assert.Equal(t, 7, v.PadRight(3, 7).At(2))
+	})
+}
+
+func TestEmpty(t *testing.T) {
+	t.Parallel()
+
+	v := vector.Empty[int]()
+	assert.True(t, v.IsZero(), "empty vector should be zero-value")
+	assert.Zero(t, v.Len(), "empty vector should have zero length")
+
+	b := vector.NewBuilder[int]().Vector()
+	assert.False(t, b.IsZero(), "built vector should be initialized")
+	assert.Zero(t, b.Len(), "built vector should have zero length")
+
+	assert.False(t, v.Append(1).IsZero(), "non-empty vector should not be zero")
+	assert.True(t, v.Append(1).Pop().IsZero(), "popping last element should return zero-value")
+}
+
+func TestShift(t *testing.T) {
+	t.Parallel()
+
+	const n, k = 4096, 100
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i + 1
+	}
+
+	v := vector.New(is...)
+
+	t.Run("Left", func(t *testing.T) {
+		s := v.ShiftLeft(k, 0)
+		require.Equal(t, n, s.Len(), "should preserve length")
+		for i := 0; i < n; i++ {
+			want := 0
+			if i < n-k {
+				want = i + k + 1
+			}
+			require.Equal(t, want, s.At(i))
+		}
+	})
+
+	t.Run("Right", func(t *testing.T) {
+		s := v.ShiftRight(k, 0)
+		require.Equal(t, n, s.Len(), "should preserve length")
+		for i := 0; i < n; i++ {
+			want := 0
+			if i >= k {
+				want = i - k + 1
+			}
+			require.Equal(t, want, s.At(i))
+		}
+	})
+
+	t.Run("Edges", func(t *testing.T) {
+		assert.Equal(t, v, v.ShiftLeft(0, 0), "zero shift should no-op")
+		assert.Equal(t, v, v.ShiftRight(0, 0), "zero shift should no-op")
+
+		s := v.ShiftLeft(n+1, -1)
+		assert.Equal(t, n, s.Len(), "should preserve length")
+		assert.Equal(t, -1, s.At(0), "should replace every element")
+
+		assert.Panics(t, func() { v.ShiftLeft(-1, 0) },
+			"should panic on negative shift")
+		assert.Panics(t, func() { v.ShiftRight(-1, 0) },
+			"should panic on negative shift")
+	})
+}
+
+func TestEquals(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	b := vector.NewBuilder[[]int]()
+	for i := 0; i < n; i++ {
+		b.Append([]int{i})
+	}
+
+	v := b.Vector()
+	assert.True(t, v.Equals(v), "vector should equal itself")
+	assert.True(t, v.Equals(v.Set(10, []int{10})), "should compare deeply")
+	assert.False(t, v.Equals(v.Set(10, []int{-1})), "should detect differing elements")
+	assert.False(t, v.Equals(v.Pop()), "should detect differing lengths")
+	assert.True(t, vector.Vector[[]int]{}.Equals(vector.NewBuilder[[]int]().Vector()),
+		"empty vectors should be equal")
+}
+
+func TestPopNValues(t *testing.T) {
+	t.Parallel()
+
+	const n, k = 4096, 100
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i
+	}
+
+	v := vector.New(is...)
+
+	rest, popped := v.PopNValues(k)
+	require.Equal(t, n-k, rest.Len(), "should remove %d elements", k)
+	assert.Equal(t, is[n-k:], popped, "should return removed elements in order")
+	for i := 0; i < rest.Len(); i++ {
+		require.Equal(t, i, rest.At(i))
+	}
+	assert.Equal(t, n, v.Len(), "should not mutate receiver")
+
+	rest, popped = v.PopNValues(n + 1)
+	assert.True(t, rest.IsZero(), "should clamp to length")
+	assert.Equal(t, is, popped, "should return every element")
+
+	rest, popped = v.PopNValues(0)
+	assert.Equal(t, v, rest, "should no-op")
+	assert.Empty(t, popped, "should return no elements")
+}
+
+func TestUpdate(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i
+	}
+
+	v := vector.New(is...)
+	incr := func(i int) int { return i + 1 }
+
+	u := v
+	for i := 0; i < n; i++ {
+		u = u.Update(i, incr)
+	}
+
+	for i := 0; i < n; i++ {
+		require.Equal(t, i+1, u.At(i), "should update element")
+		require.Equal(t, i, v.At(i), "should not mutate receiver")
+	}
+
+	assert.Panics(t, func() { v.Update(n, incr) }, "should panic when out of bounds")
+	assert.Panics(t, func() { v.Update(-1, incr) }, "should panic when out of bounds")
+}
+
+func TestUpdateWhere(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i
+	}
+
+	v := vector.New(is...)
+	even := func(i int) bool { return i%2 == 0 }
+	negate := func(i int) int { return -i }
+
+	u := v.UpdateWhere(even, negate)
+	require.Equal(t, n, u.Len(), "should preserve length")
+	for i := 0; i < n; i++ {
+		want := i
+		if even(i) {
+			want = -i
+		}
+		require.Equal(t, want, u.At(i))
+		require.Equal(t, i, v.At(i), "should not mutate receiver")
+	}
+
+	none := v.UpdateWhere(func(int) bool { return false }, negate)
+	assert.Equal(t, v, none, "should return receiver when nothing matches")
+}
+
+func TestAppendBounded(t *testing.T) {
+	t.Parallel()
+
+	v := vector.New(1, 2, 3)
+
+	v2, err := v.AppendBounded(5, 4, 5)
+	require.NoError(t, err, "should append when within bound")
+	assert.Equal(t, 5, v2.Len())
+
+	v3, err := v.AppendBounded(5, 4, 5, 6, 7)
+	require.ErrorIs(t, err, vector.ErrCapacityExceeded, "should report overflow")
+	assert.Contains(t, err.Error(), "dropped 2", "should report dropped count")
+	assert.Equal(t, 5, v3.Len(), "should append elements that fit")
+	assert.Equal(t, 5, v3.At(4))
+
+	v4, err := v.AppendBounded(2, 4)
+	require.ErrorIs(t, err, vector.ErrCapacityExceeded, "should report overflow")
+	assert.Equal(t, v, v4, "should not append when already over bound")
+}
+
+func TestBuilderReverse(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range []int{0, 1, 2, 31, 32, 33, 1000, 4096} {
+		b := vector.NewBuilder[int]()
+		for i := 0; i < n; i++ {
+			b.Append(i)
+		}
+
+		b.Reverse()
+
+		v := b.Vector()
+		require.Equal(t, n, v.Len())
+		for i := 0; i < n; i++ {
+			require.Equal(t, n-1-i, v.At(i), "n=%d", n)
+		}
+	}
+
+	t.Run("Twice", func(t *testing.T) {
+		const n = 4096
+
+		is := make([]int, n)
+		for i := range is {
+			is[i] = i
+		}
+
+		b := vector.NewBuilder[int]()
+		b.Append(is...)
+		b.Reverse()
+		b.Reverse()
+		assert.True(t, vector.Equal(vector.New(is...), b.Vector()),
+			"double reverse should be identity")
+	})
+}
+
+func TestTee(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i
+	}
+
+	var sum, count, largest int
+	var order []string
+
+	vector.New(is...).Tee(
+		func(i int) { sum += i },
+		func(int) { count++ },
+		func(i int) { largest = max(largest, i) },
+		func(i int) {
+			if i < 2 {
+				order = append(order, "a")
+			}
+		},
+		func(i int) {
+			if i < 2 {
+				order = append(order, "b")
+			}
+		})
+
+	assert.Equal(t, n*(n-1)/2, sum)
+	assert.Equal(t, n, count)
+	assert.Equal(t, n-1, largest)
+	assert.Equal(t, []string{"a", "b", "a", "b"}, order,
+		"should call consumers in order for each element")
+}
+
+func TestSlice3(t *testing.T) {
+	t.Parallel()
+
+	v := vector.New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+
+	s := v.Slice3(2, 5, 7)
+	require.Equal(t, 3, s.Len())
+	assert.Equal(t, 2, s.At(0))
+	assert.Equal(t, 4, s.At(2))
+
+	s = s.Append(-1)
+	assert.Equal(t, 5, v.At(5), "appending to result should not affect receiver")
+
+	assert.Panics(t, func() { v.Slice3(-1, 5, 7) }, "should panic when low < 0")
+	assert.Panics(t, func() { v.Slice3(6, 5, 7) }, "should panic when low > high")
+	assert.Panics(t, func() { v.Slice3(2, 8, 7) }, "should panic when high > max")
+	assert.Panics(t, func() { v.Slice3(2, 5, 11) }, "should panic when max > len")
+}
+
+func TestAppendRepeat(t *testing.T) {
+	t.Parallel()
+
+	for _, k := range []int{0, 1, 5, 31, 32, 33, 100} {
+		for _, n := range []int{1, 31, 32, 33, 64, 1000, 40000} {
+			v := vector.New[int]()
+			for i := 0; i < k; i++ {
+				v = v.Append(i)
+			}
+
+			r := v.AppendRepeat(-1, n)
+			require.Equal(t, k+n, r.Len(), "k=%d n=%d", k, n)
+			for i := 0; i < k+n; i++ {
+				want := -1
+				if i < k {
+					want = i
+				}
+				require.Equal(t, want, r.At(i), "k=%d n=%d i=%d", k, n, i)
+			}
+
+			// writes to one repeated leaf must not leak into the others
+			mid := k + n/2
+			r2 := r.Set(mid, 7).Append(8)
+			require.Equal(t, 7, r2.At(mid))
+
+			c := vector.NewCOW(r)
+			c.Set(mid, 9)
+			c.Pop()
+
+			for i := k; i < k+n; i++ {
+				require.Equal(t, -1, r.At(i), "should not mutate shared leaf")
+				if i != mid {
+					require.Equal(t, -1, r2.At(i), "should not mutate shared leaf")
+				}
+			}
+		}
+	}
+
+	v := vector.New(1, 2, 3)
+	assert.Equal(t, v, v.AppendRepeat(0, 0), "should no-op when n is zero")
+	assert.Equal(t, v, v.AppendRepeat(0, -1), "should no-op when n is negative")
+}
+
+func TestReverse(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i
+	}
+
+	v := vector.New(is...)
+	r := v.Reverse()
+	require.Equal(t, n, r.Len())
+	for i := 0; i < n; i++ {
+		require.Equal(t, n-1-i, r.At(i))
+		require.Equal(t, i, v.At(i), "should not mutate receiver")
+	}
+
+	assert.True(t, vector.Vector[int]{}.Reverse().IsZero())
+}
+
+func TestReadAt(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	is := make([]int, n)
+	for i := range is {
+		is[i] = i
+	}
+
+	v := vector.New(is...)
+	page := make([]int, 100)
+
+	for start := 0; start < n; start += len(page) {
+		k := v.ReadAt(page, start)
+		require.Equal(t, min(len(page), n-start), k, "start=%d", start)
+		assert.Equal(t, is[start:start+k], page[:k])
+	}
+
+	assert.Zero(t, v.ReadAt(page, n), "should copy nothing at end")
+	assert.Zero(t, v.ReadAt(nil, 0), "should copy nothing into empty buffer")
+	assert.Panics(t, func() { v.ReadAt(page, -1) }, "should panic when out of bounds")
+	assert.Panics(t, func() { v.ReadAt(page, n+1) }, "should panic when out of bounds")
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	const n = 100
+
+	v := vector.Iota(0, n)
+	got := v.Format(", ", func(i int) string { return fmt.Sprintf("%02x", i) })
+
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = fmt.Sprintf("%02x", i)
+	}
+	assert.Equal(t, strings.Join(parts, ", "), got)
+
+	assert.Equal(t, "", vector.Vector[int]{}.Format(",", strconv.Itoa))
+	assert.Equal(t, "7", vector.New(7).Format(",", strconv.Itoa))
+}
+
+func TestReversed(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	v := vector.Iota(0, n)
+	r := v.Reversed()
+
+	require.Equal(t, n, r.Len())
+	for i := 0; i < n; i++ {
+		require.Equal(t, n-1-i, r.At(i))
+	}
+
+	assert.Panics(t, func() { r.At(-1) })
+	assert.Panics(t, func() { r.At(n) })
+
+	m := r.Materialize()
+	assert.True(t, vector.Equal(v.Reverse(), m))
+	assert.Equal(t, 0, v.At(0), "should not modify the original")
+
+	assert.Zero(t, vector.Vector[int]{}.Reversed().Len())
+}
+
+// seq returns the n consecutive integers starting at from.
+func seq(from, n int) []int {
+	is := make([]int, n)
+	for i := range is {
+		is[i] = from + i
+	}
+
+	return is
+}
+
+// requireMerged checks that got is a followed by at most one leaf's worth
+// of trailing elements of ancestor, followed by ys.
+func requireMerged(t *testing.T, got, a, ancestor vector.Vector[int], ys []int) {
+	t.Helper()
+
+	d := got.Len() - a.Len() - len(ys)
+	require.True(t, d >= 0 && d <= min(32, ancestor.Len()),
+		"should copy at most the last leaf of the ancestor (copied %d)", d)
+	require.True(t, vector.Equal(a, got.Slice(0, a.Len())), "should start with a")
+	require.True(t, vector.Equal(ancestor.Slice(ancestor.Len()-d, ancestor.Len()),
+		got.Slice(a.Len(), a.Len()+d)))
+	require.True(t, vector.Equal(vector.New(ys...), got.Slice(a.Len()+d, got.Len())),
+		"should end with the elements appended by the other branch")
+}
+
+func TestAppendShared(t *testing.T) {
+	t.Parallel()
+
+	for _, m := range []int{0, 10, 32, 1000, 32*32 + 5*32 + 10} {
+		for _, sz := range [][2]int{{0, 0}, {5, 7}, {0, 40}, {40000, 33}, {3, 40000}} {
+			xs, ys := seq(-1<<30, sz[0]), seq(1<<30, sz[1])
+
+			ancestor := vector.Iota(0, m)
+			a, b := ancestor.Append(xs...), ancestor.Append(ys...)
+
+			got := a.AppendShared(b)
+			requireMerged(t, got, a, ancestor, ys)
+			requireMerged(t, ancestor.AppendShared(b), ancestor, ancestor, ys)
+			requireMerged(t, a.AppendShared(ancestor), a, ancestor, nil)
+			require.True(t, vector.Equal(a, a.AppendShared(a)), "m=%d", m)
+
+			// writes to the result must not leak into the branches
+			if got.Len() > 0 {
+				got.Set(got.Len()-1, 0)
+			}
+			require.True(t, vector.Equal(vector.New(append(seq(0, m), ys...)...), b))
+		}
+	}
+
+	// Unrelated vectors are concatenated.
+	for _, m := range []int{1, 31, 32, 33, 1024} {
+		got := vector.Iota(0, m).AppendShared(vector.Iota(m, 4096))
+		require.True(t, vector.Equal(vector.Iota(0, m+4096), got), "m=%d", m)
+	}
+
+	assert.Equal(t, 0, vector.Iota(0, 10).AppendShared(vector.Vector[int]{}).At(0))
+}
+
+func TestAppendSharedEqualValues(t *testing.T) {
+	t.Parallel()
+
+	const x, y = -1, -2
+
+	// Both branches appended x independently, so it is not shared history
+	// and must appear twice.
+	for _, m := range []int{0, 10, 32, 1000, 32*32 + 5*32 + 10} {
+		ancestor := vector.Iota(0, m)
+		a, b := ancestor.Append(x), ancestor.Append(x, y)
+
+		got := a.AppendShared(b)
+		requireMerged(t, got, a, ancestor, []int{x, y})
+		assert.Equal(t, 2, vector.Histogram(got)[x], "m=%d", m)
+	}
+
+	assert.True(t, vector.Equal(vector.New(x, x, y), vector.New(x).AppendShared(vector.New(x, y))))
+}
+
+func TestAppendSharedGraft(t *testing.T) {
+	t.Parallel()
+
+	ancestor := vector.Iota(0, 32*32+5*32+10)
+	b := ancestor.Append(seq(1<<20, 4096)...)
+
+	// The branches share the trie of the ancestor, but not its tail of 10
+	// elements.  a appended 22 elements, so the elements of b that follow
+	// the shared leaves line up with the leaves of a and are grafted into
+	// the result.
+	a := ancestor.Append(seq(-22, 22)...)
+	got := a.AppendShared(b)
+	requireMerged(t, got, a, ancestor, seq(1<<20, 4096))
+	assert.Equal(t, a.Len()+10+4096, got.Len())
+	assert.GreaterOrEqual(t, vector.SharedNodes(a, got), ancestor.Len()/32,
+		"should share the leaves of a")
+	assert.GreaterOrEqual(t, vector.SharedNodes(b, got), b.Len()/32-ancestor.Len()/32-2,
+		"should graft the leaves appended by b")
+}
+
+func TestCountLeadingTrailing(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	isZero := func(i int) bool { return i == 0 }
+
+	for _, pad := range []int{0, 1, 31, 32, 33, 1000} {
+		v := vector.New[int]().AppendRepeat(0, pad).Append(1, 2, 3).AppendRepeat(0, n-pad)
+		assert.Equal(t, pad, v.CountLeading(isZero), "pad=%d", pad)
+		assert.Equal(t, n-pad, v.CountTrailing(isZero), "pad=%d", pad)
+	}
+
+	zeros := vector.New[int]().AppendRepeat(0, n)
+	assert.Equal(t, n, zeros.CountLeading(isZero))
+	assert.Equal(t, n, zeros.CountTrailing(isZero))
+
+	assert.Zero(t, vector.Vector[int]{}.CountLeading(isZero))
+	assert.Zero(t, vector.Vector[int]{}.CountTrailing(isZero))
+}
+
+func TestBuilderSnapshot(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	b := vector.NewBuilder[int]()
+	var snaps []vector.Vector[int]
+	for i := 0; i < n; i++ {
+		b.Append(i)
+		if i%500 == 0 {
+			snaps = append(snaps, b.Snapshot())
+		}
+	}
+
+	// scribble over everything the snapshots might share
+	for i := 0; i < b.Len(); i++ {
+		b.Set(i, -i)
+	}
+	b.Reverse()
+	b.Pop()
+	b.Append(-1)
+
+	for k, s := range snaps {
+		require.Equal(t, k*500+1, s.Len())
+		for i := 0; i < s.Len(); i++ {
+			require.Equal(t, i, s.At(i), "snapshot %d, index %d", k, i)
+		}
+	}
+
+	v := b.Vector()
+	require.Equal(t, n, v.Len())
+	assert.Equal(t, -(n - 1), v.At(0))
+	assert.Equal(t, -1, v.At(n-1))
+}
+
+func TestBuilderWith(t *testing.T) {
+	t.Parallel()
+
+	b := vector.NewBuilder[int]()
+	assert.Same(t, b, b.With(1, 2), "should return the receiver")
+
+	v := b.With(3).With().With(4, 5).Vector()
+	assert.True(t, vector.Equal(vector.New(1, 2, 3, 4, 5), v))
+}
+
+func TestCut(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	v := vector.Iota(0, n)
+	for _, r := range [][2]int{{0, 0}, {0, n}, {0, 1}, {n - 1, n}, {100, 1000}, {31, 33}} {
+		start, end := r[0], r[1]
+		rest, removed := v.Cut(start, end)
+
+		require.Equal(t, n-(end-start), rest.Len(), "cut [%d, %d)", start, end)
+		require.Equal(t, end-start, removed.Len(), "cut [%d, %d)", start, end)
+
+		assert.True(t, vector.Equal(v.Slice(start, end), removed), "cut [%d, %d)", start, end)
+		for i := 0; i < rest.Len(); i++ {
+			want := i
+			if i >= start {
+				want += end - start
+			}
+			require.Equal(t, want, rest.At(i), "cut [%d, %d), index %d", start, end, i)
+		}
+	}
+
+	assert.Equal(t, n, v.Len(), "should not modify the original")
+	assert.Panics(t, func() { v.Cut(-1, 0) })
+	assert.Panics(t, func() { v.Cut(2, 1) })
+	assert.Panics(t, func() { v.Cut(0, n+1) })
+}
+
+func TestSetFrom(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	v := vector.Iota(0, n)
+	indices := vector.New(0, 100, n-1, 100, 2048)
+	values := vector.New(-1, -2, -3, -4, -5)
+
+	got := v.SetFrom(indices, values)
+	require.Equal(t, n, got.Len())
+	for i := 0; i < n; i++ {
+		want := i
+		switch i {
+		case 0:
+			want = -1
+		case 100:
+			want = -4 // later writes win
+		case n - 1:
+			want = -3
+		case 2048:
+			want = -5
+		}
+		require.Equal(t, want, got.At(i), "index %d", i)
+	}
+	assert.Equal(t, 0, v.At(0), "should not modify the original")
+
+	assert.Panics(t, func() { v.SetFrom(indices, values.Pop()) }, "should panic on length mismatch")
+	assert.Panics(t, func() { v.SetFrom(vector.New(0, n), vector.New(1, 2)) }, "should panic on bad index")
+	assert.True(t, vector.Equal(v, v.SetFrom(vector.Vector[int]{}, vector.Vector[int]{})))
+}
+
+func TestBuilderValues(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	b := vector.NewBuilder[int]()
+	for range b.Values() {
+		t.Fatal("empty builder should yield nothing")
+	}
+
+	for i := 0; i < n; i++ {
+		b.Append(i)
+
+		if i == 100 || i == n-1 {
+			var got []int
+			for x := range b.Values() {
+				got = append(got, x)
+			}
+			require.Len(t, got, i+1, "should reflect the current contents")
+			for j, x := range got {
+				require.Equal(t, j, x)
+			}
+		}
+	}
+
+	var seen int
+	for range b.Values() {
+		if seen++; seen == 10 {
+			break
+		}
+	}
+	assert.Equal(t, 10, seen, "should stop on break")
+
+	b.Append(n)
+	assert.Equal(t, n+1, b.Len(), "should remain usable after iteration")
+}
+
+func TestRotateInPlaceBuilder(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	v := vector.Iota(0, n)
+	for _, k := range []int{0, 1, 31, 32, 33, n / 3, n - 1, n, n + 5, -1, -n - 5} {
+		got := v.RotateInPlaceBuilder(k)
+		require.Equal(t, n, got.Len())
+
+		shift := ((k % n) + n) % n
+		for i := 0; i < n; i++ {
+			require.Equal(t, (i+shift)%n, got.At(i), "k=%d, index %d", k, i)
+		}
+	}
+
+	assert.Equal(t, 0, v.At(0), "should not modify the original")
+	assert.Zero(t, vector.Vector[int]{}.RotateInPlaceBuilder(3).Len())
+}
+
+func TestGrow(t *testing.T) {
+	t.Parallel()
+
+	const n = 40000
+
+	v := vector.Iota(0, 100)
+	g := v.Grow(n)
+	assert.True(t, vector.Equal(v, g), "should preserve contents")
+	assert.Greater(t, vector.SharedNodes(v, g), 0, "should share elements with the original")
+
+	for i := 100; i < 2*n; i++ {
+		g = g.Append(i)
+	}
+	require.Equal(t, 2*n, g.Len(), "should append past grown capacity")
+	for i := 0; i < 2*n; i++ {
+		require.Equal(t, i, g.At(i))
+	}
+
+	for i := 2*n - 1; i >= 0; i-- {
+		g = g.Pop()
+		require.Equal(t, i, g.Len())
+	}
+	g = g.Append(1, 2, 3)
+	assert.True(t, vector.Equal(vector.New(1, 2, 3), g))
+
+	z := vector.Vector[int]{}.Grow(n).Append(42)
+	assert.Equal(t, 42, z.At(0), "should grow the zero-value vector")
+	assert.True(t, vector.Vector[int]{}.Grow(0).IsZero())
+	assert.Panics(t, func() { v.Grow(-1) })
+}
+
+func TestDeepClone(t *testing.T) {
+	t.Parallel()
+
+	const n = 100
+
+	b := vector.NewBuilder[[]int]()
+	for i := 0; i < n; i++ {
+		b.Append([]int{i})
+	}
+	v := b.Vector()
+
+	shallow := v.Set(0, v.At(0))
+	deep := v.DeepClone(func(s []int) []int { return append([]int(nil), s...) })
+	require.True(t, v.Equals(deep))
+	assert.Zero(t, vector.SharedNodes(v, deep), "should not share any nodes")
+
+	for i := 0; i < n; i++ {
+		v.At(i)[0] = -1
+	}
+
+	assert.Equal(t, -1, shallow.At(n - 1)[0], "shallow copies should share elements")
+	for i := 0; i < n; i++ {
+		require.Equal(t, i, deep.At(i)[0], "deep copy should be isolated")
+	}
+}
+
+func TestStep(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	v := vector.Iota(0, n)
+	collect := func(seq iter.Seq2[int, int]) (is []int) {
+		for i, x := range seq {
+			require.Equal(t, i, x)
+			is = append(is, i)
+		}
+		return
+	}
+
+	for _, tt := range []struct {
+		start, stop, step int
+	}{
+		{0, n, 1}, {0, n, 10}, {5, 100, 33}, {n - 1, -1, -1}, {n - 1, -1, -7},
+		{100, 5, -33}, {5, 5, 1}, {5, 5, -1}, {10, 5, 1}, {5, 10, -1},
+	} {
+		var want []int
+		for i := tt.start; (tt.step > 0 && i < tt.stop) || (tt.step < 0 && i > tt.stop); i += tt.step {
+			want = append(want, i)
+		}
+
+		got := collect(v.Step(tt.start, tt.stop, tt.step))
+		assert.Equal(t, want, got, "Step(%d, %d, %d)", tt.start, tt.stop, tt.step)
+	}
+
+	var seen int
+	for range v.Step(0, n, 2) {
+		if seen++; seen == 5 {
+			break
+		}
+	}
+	assert.Equal(t, 5, seen, "should stop on break")
+
+	assert.Panics(t, func() { v.Step(0, n, 0) }, "should panic on zero step")
+	assert.Panics(t, func() { v.Step(0, n+1, 1) })
+	assert.Panics(t, func() { v.Step(n, 0, -1) })
+	assert.Panics(t, func() { v.Step(10, -2, -1) })
+}
+
+func TestBuilderAppendSeqs(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	b := vector.NewBuilder[int]()
+	b.Append(-1)
+	b.AppendSeqs(
+		vector.NewBuilder[int]().With(0, 1, 2).Values(),
+		slices.Values([]int{}),
+		func(yield func(int) bool) {
+			for i := 3; i < n; i++ {
+				if !yield(i) {
+					return
+				}
+			}
+		},
+	)
+	b.AppendSeqs()
+
+	v := b.Vector()
+	require.Equal(t, n+1, v.Len())
+	for i := 0; i <= n; i++ {
+		require.Equal(t, i-1, v.At(i))
+	}
+}
+
+func TestTailSpace(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 32, vector.Vector[int]{}.TailSpace())
+	assert.Equal(t, 31, vector.New(1).TailSpace())
+	assert.Equal(t, 0, vector.Iota(0, 32).TailSpace())
+	assert.Equal(t, 31, vector.Iota(0, 33).TailSpace())
+	assert.Equal(t, 0, vector.Iota(0, 4096).TailSpace())
+
+	// batching appends to the tail's boundary
+	v := vector.Iota(0, 100)
+	v = v.AppendRepeat(0, v.TailSpace())
+	assert.Equal(t, 128, v.Len())
+	assert.Zero(t, v.TailSpace())
+}
+
+func TestPushBounded(t *testing.T) {
+	t.Parallel()
+
+	const n, bound = 4096, 100
+
+	var v vector.Vector[int]
+	history := make([]vector.Vector[int], 0, n)
+	for i := 0; i < n; i++ {
+		v = v.PushBounded(bound, i)
+		history = append(history, v)
+
+		require.Equal(t, min(i+1, bound), v.Len())
+		require.Equal(t, i, v.At(v.Len()-1), "should append the item")
+		require.Equal(t, max(0, i-bound+1), v.At(0), "should drop the oldest")
+	}
+
+	// earlier snapshots are unaffected
+	assert.Equal(t, 51, history[50].Len())
+	assert.Equal(t, 0, history[50].At(0))
+
+	assert.Panics(t, func() { v.PushBounded(0, 1) })
+}